@@ -26,6 +26,18 @@ Options:
 %s
 `
 
+	asciiFlag  = "ascii"
+	asciiUsage = "Escape all non-ASCII characters in strings"
+
+	escapeHTMLFlag  = "escape-html"
+	escapeHTMLUsage = "Escape <, > and & in strings"
+
+	escapeLineTerminatorsFlag  = "escape-line-terminators"
+	escapeLineTerminatorsUsage = "Escape U+2028 and U+2029 in strings"
+
+	strictUTF8Flag  = "strict-utf8"
+	strictUTF8Usage = "Fail on invalid UTF-8 in strings instead of replacing it with U+FFFD"
+
 	arrayFlagShorthand = "a"
 	arrayFlag          = "array"
 	arrayUsage         = "Generate array of root objects (0 means do not wrap in array)"
@@ -39,6 +51,9 @@ Options:
 	noSortKeysUsage         = "Do not sort keys in objects"
 	noSortKeysDefault       = false
 
+	nowFlag  = "now"
+	nowUsage = "Time in RFC 3339 which relative times in schema are relative to (current time if not set). The time used is printed to stderr"
+
 	outFlagShorthand = "o"
 	outFlag          = "output"
 	outUsage         = "JSON output"
@@ -48,6 +63,9 @@ Options:
 	outBuffSizeUsage   = "Buffer size for JSON output (0 means no buffer)"
 	outBuffSizeDefault = 1024
 
+	seedFlag  = "seed"
+	seedUsage = "Seed for random generator (random if not set). The seed used is printed to stderr"
+
 	streamFlagShorthand = "s"
 	streamFlag          = "stream"
 	streamUsage         = "Stream root objects delimited by newline (-1 means endless)"
@@ -69,6 +87,12 @@ func run() error {
 	out := fs.StringP(outFlag, outFlagShorthand, outDefault, outUsage)
 	outBuffSize := fs.Uint(outBuffSizeFlag, outBuffSizeDefault, outBuffSizeUsage)
 	stream := fs.Int64P(streamFlag, streamFlagShorthand, 0, streamUsage)
+	seed := fs.Int64(seedFlag, 0, seedUsage)
+	now := fs.String(nowFlag, "", nowUsage)
+	ascii := fs.Bool(asciiFlag, false, asciiUsage)
+	escapeHTML := fs.Bool(escapeHTMLFlag, false, escapeHTMLUsage)
+	escapeLineTerminators := fs.Bool(escapeLineTerminatorsFlag, false, escapeLineTerminatorsUsage)
+	strictUTF8 := fs.Bool(strictUTF8Flag, false, strictUTF8Usage)
 	var arrayLen schema.Length
 	fs.VarP(&arrayLen, arrayFlag, arrayFlagShorthand, arrayUsage)
 
@@ -125,6 +149,23 @@ func run() error {
 	ctx := schema.NewContext()
 	defer ctx.Close()
 	ctx.SetSortKeys(!*noSortKeys)
+	strEnc := schema.StringEncoder{
+		EscapeHTML:            *escapeHTML,
+		EscapeLineTerminators: *escapeLineTerminators,
+		ASCII:                 *ascii,
+	}
+	if *strictUTF8 {
+		strEnc.InvalidUTF8 = schema.FailOnInvalidUTF8
+	}
+	ctx.SetStringEncoder(strEnc)
+	if fs.Changed(nowFlag) {
+		t, err := time.Parse(time.RFC3339Nano, *now)
+		if err != nil {
+			return fmt.Errorf("invalid '--%s': %w", nowFlag, err)
+		}
+		ctx.SetNow(t)
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "now: %s\n", ctx.Now().Format(time.RFC3339Nano))
 
 	for name := range sch.Files {
 		file, found := (*files)[name]
@@ -143,11 +184,11 @@ func run() error {
 		w = bw
 	}
 
-	var seed uint64
-	if err := binary.Read(crand.Reader, binary.BigEndian, &seed); err != nil {
-		seed = uint64(time.Now().UnixNano())
+	if !fs.Changed(seedFlag) {
+		*seed = randomSeed()
 	}
-	rnd := rand.New(rand.NewSource(int64(seed)))
+	_, _ = fmt.Fprintf(os.Stderr, "seed: %d\n", *seed)
+	rnd := rand.New(rand.NewSource(*seed))
 
 	switch {
 	case arrayLen.Max != 0:
@@ -161,3 +202,11 @@ func run() error {
 		return sch.GenerateJSON(ctx, w, rnd)
 	}
 }
+
+func randomSeed() int64 {
+	var seed int64
+	if err := binary.Read(crand.Reader, binary.BigEndian, &seed); err != nil {
+		seed = time.Now().UnixNano()
+	}
+	return seed
+}
@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -19,12 +20,15 @@ var defaultArrayLength = Length{
 type Array struct {
 	Length   Length
 	Elements Node
+	// Unique makes all elements of array distinct
+	Unique bool
 }
 
 func (a *Array) UnmarshalYAML(value *yaml.Node) error {
 	aux := struct {
 		Length   Length `yaml:"length"`
 		Elements *node  `yaml:"elements"`
+		Unique   bool   `yaml:"unique"`
 	}{
 		Length: defaultArrayLength,
 	}
@@ -40,6 +44,7 @@ func (a *Array) UnmarshalYAML(value *yaml.Node) error {
 	*a = Array{
 		Length:   aux.Length,
 		Elements: aux.Elements.Node,
+		Unique:   aux.Unique,
 	}
 	return nil
 }
@@ -49,6 +54,13 @@ func (a *Array) GenerateJSON(ctx *Context, w io.Writer, r *rand.Rand) error {
 		return err
 	}
 	elNum := a.Length.Rand(r)
+	if a.Unique {
+		if err := a.writeUniqueElements(ctx, w, r, elNum); err != nil {
+			return err
+		}
+		_, err := w.Write([]byte{']'})
+		return err
+	}
 	for i := uint64(0); i < elNum; i++ {
 		if i > 0 {
 			if _, err := w.Write([]byte{','}); err != nil {
@@ -63,6 +75,29 @@ func (a *Array) GenerateJSON(ctx *Context, w io.Writer, r *rand.Rand) error {
 	return err
 }
 
+func (a *Array) writeUniqueElements(ctx *Context, w io.Writer, r *rand.Rand, elNum uint64) error {
+	var buff bytes.Buffer
+	els, err := sampleUnique(ctx, r, elNum, a.Elements, "element", func() ([]byte, error) {
+		buff.Reset()
+		err := a.Elements.GenerateJSON(ctx, &buff, r)
+		return append([]byte(nil), buff.Bytes()...), err
+	}, a.wrapIndexErr)
+	if err != nil {
+		return err
+	}
+	for i, el := range els {
+		if i > 0 {
+			if _, err := w.Write([]byte{','}); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(el); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (a *Array) Walk(fn WalkFn) (err error) {
 	return a.wrapErr(Walk(a.Elements, fn))
 }
@@ -2,8 +2,10 @@ package schema
 
 import (
 	"bytes"
+	"encoding/json"
 	"io/ioutil"
 	"math/rand"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -89,3 +91,126 @@ func BenchmarkArray_GenerateJSON_Large(b *testing.B) {
 		_ = n.GenerateJSON(nil, ioutil.Discard, nil)
 	}
 }
+
+func TestArray_GenerateJSON_Unique(t *testing.T) {
+	tests := []struct {
+		name     string
+		length   uint64
+		elements Node
+		wantErr  string
+	}{
+		{
+			name:     "all values",
+			length:   5,
+			elements: &Integer{Range: NewIntRange(1, 5)},
+		},
+		{
+			name:     "too small domain",
+			length:   3,
+			elements: &String{StringRander: StringChoices{"a", "b", "b"}},
+			wantErr:  "unable to generate 3 unique elements: there are only 2 distinct values",
+		},
+		{
+			name:   "unreachable choices",
+			length: 2,
+			elements: &String{StringRander: weightedStringChoices{
+				StringChoices: StringChoices{"a", "b"},
+				weights:       mustWeights(t, 1, 0),
+			}},
+			wantErr: "unable to generate 2 unique elements: there are only 1 distinct values",
+		},
+		{
+			name:     "uncountable duplicates",
+			length:   2,
+			elements: testNode("n"),
+			wantErr:  "[1]: unable to generate unique element: got 1000 duplicates in a row",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Array{
+				Length: Length{
+					Min: tt.length,
+					Max: tt.length,
+				},
+				Elements: tt.elements,
+				Unique:   true,
+			}
+			var w bytes.Buffer
+			err := a.GenerateJSON(NewContext(), &w, rand.New(rand.NewSource(1)))
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			var got []int64
+			require.NoError(t, json.Unmarshal(w.Bytes(), &got))
+			require.ElementsMatch(t, []int64{1, 2, 3, 4, 5}, got)
+		})
+	}
+}
+
+func TestArray_GenerateJSON_UniqueWholeDomain(t *testing.T) {
+	tests := []struct {
+		name     string
+		elements Node
+	}{
+		{
+			name:     "enumerable",
+			elements: &Integer{Range: NewIntRange(1, 1000)},
+		},
+		{
+			name: "countable",
+			elements: &Float{
+				Range:    &FloatRange{Min: 0.001, Max: 1},
+				Step:     0.001,
+				Decimals: 3,
+				Format:   PlainNumberFormat,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Array{
+				Length:   Length{Min: 1000, Max: 1000},
+				Elements: tt.elements,
+				Unique:   true,
+			}
+			for seed := int64(0); seed < 20; seed++ {
+				var w bytes.Buffer
+				require.NoError(t, a.GenerateJSON(NewContext(), &w, rand.New(rand.NewSource(seed))), "seed %d", seed)
+				var got []float64
+				require.NoError(t, json.Unmarshal(w.Bytes(), &got))
+				require.Len(t, got, 1000)
+			}
+		})
+	}
+}
+
+func TestArray_GenerateJSON_UniqueFileLines(t *testing.T) {
+	f, err := ioutil.TempFile("", "lines")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString("a\nb\na\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	ctx := NewContext()
+	defer ctx.Close()
+	require.NoError(t, ctx.AddFile("lines", f.Name()))
+
+	a := &Array{
+		Length:   Length{Min: 2, Max: 2},
+		Elements: &String{StringRander: StringFile("lines")},
+		Unique:   true,
+	}
+	var w bytes.Buffer
+	require.NoError(t, a.GenerateJSON(ctx, &w, rand.New(rand.NewSource(1))))
+	var got []string
+	require.NoError(t, json.Unmarshal(w.Bytes(), &got))
+	require.ElementsMatch(t, []string{"a", "b"}, got)
+
+	a.Length = Length{Min: 3, Max: 3}
+	require.EqualError(t, a.GenerateJSON(ctx, &w, rand.New(rand.NewSource(1))),
+		"unable to generate 3 unique elements: there are only 2 distinct values")
+}
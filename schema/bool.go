@@ -3,20 +3,62 @@ package schema
 import (
 	"io"
 	"math/rand"
+
+	"gopkg.in/yaml.v3"
 )
 
-type Bool struct{}
+const defaultBoolProbability Probability = 0.5
+
+type Bool struct {
+	// Probability of true, nil means defaultBoolProbability
+	Probability *Probability
+}
 
 var (
 	trueJSON  = []byte("true")
 	falseJSON = []byte("false")
 )
 
+func (b *Bool) UnmarshalYAML(value *yaml.Node) error {
+	var aux struct {
+		Probability *Probability `yaml:"probability"`
+	}
+	if err := value.Decode(&aux); err != nil {
+		return err
+	}
+	*b = Bool{
+		Probability: aux.Probability,
+	}
+	return nil
+}
+
 func (b Bool) GenerateJSON(_ *Context, w io.Writer, r *rand.Rand) error {
+	p := defaultBoolProbability
+	if b.Probability != nil {
+		p = *b.Probability
+	}
 	v := falseJSON
-	if r.Float64() < 0.5 {
+	if p.Happens(r) {
 		v = trueJSON
 	}
 	_, err := w.Write(v)
 	return err
 }
+
+func (b Bool) Count(ctx *Context) (uint64, bool) {
+	vs, _, _ := b.Enumerate(ctx)
+	return uint64(len(vs)), true
+}
+
+func (b Bool) Enumerate(*Context) ([][]byte, bool, error) {
+	if b.Probability == nil {
+		return [][]byte{falseJSON, trueJSON}, true, nil
+	}
+	switch *b.Probability {
+	case 0:
+		return [][]byte{falseJSON}, true, nil
+	case 1:
+		return [][]byte{trueJSON}, true, nil
+	}
+	return [][]byte{falseJSON, trueJSON}, true, nil
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
 )
 
 func TestBool_GenerateJSON(t *testing.T) {
@@ -23,6 +24,23 @@ func TestBool_GenerateJSON(t *testing.T) {
 	}
 }
 
+func TestBool_GenerateJSON_Probability(t *testing.T) {
+	var (
+		b Bool
+		w bytes.Buffer
+	)
+	if assert.NoError(t, yaml.Unmarshal([]byte("probability: 1"), &b)) &&
+		assert.NoError(t, b.GenerateJSON(nil, &w, rand.New(fakeSource(1<<62)))) {
+		assert.Equal(t, string(trueJSON), w.String())
+	}
+	w.Reset()
+	if assert.NoError(t, yaml.Unmarshal([]byte("probability: 0"), &b)) &&
+		assert.NoError(t, b.GenerateJSON(nil, &w, rand.New(fakeSource(0)))) {
+		assert.Equal(t, string(falseJSON), w.String())
+	}
+	assert.Error(t, yaml.Unmarshal([]byte("probability: 1.5"), &b))
+}
+
 func BenchmarkBool_GenerateJSON(b *testing.B) {
 	var n Bool
 	for i := 0; i < b.N; i++ {
@@ -0,0 +1,115 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// bounds are bounds of a range before they are parsed as numbers
+type bounds struct {
+	// min and max are empty if they are not given
+	min, max                   string
+	exclusiveMin, exclusiveMax bool
+	// defaultExclusiveMax is true if exclusiveMax is set by default
+	defaultExclusiveMax bool
+}
+
+// unmarshalBounds unmarshals bounds of a range given in one of the forms:
+//
+//	max
+//	[min, max]
+//	"[min, max)": brackets are for inclusive and parentheses are for exclusive bounds
+//	{min: min, max: max, exclusiveMin: bool, exclusiveMax: bool}
+//
+// exclusiveMax is the default for first two forms, min is always inclusive in them.
+// Ranges with min equal to max should include max regardless of the default.
+func unmarshalBounds(value *yaml.Node, exclusiveMax bool) (bounds, error) {
+	b := bounds{
+		exclusiveMax:        exclusiveMax,
+		defaultExclusiveMax: exclusiveMax,
+	}
+	switch value.Kind {
+	case yaml.ScalarNode:
+		if s := strings.TrimSpace(value.Value); strings.HasPrefix(s, "[") || strings.HasPrefix(s, "(") {
+			var err error
+			if b, err = parseInterval(s); err != nil {
+				return bounds{}, &yamlError{
+					line: value.Line,
+					err:  err,
+				}
+			}
+			return b, nil
+		}
+		b.max = value.Value
+	case yaml.SequenceNode:
+		if len(value.Content) != 2 {
+			return bounds{}, &yamlError{
+				line: value.Line,
+				err:  fmt.Errorf("range should have 2 elements, got: %d", len(value.Content)),
+			}
+		}
+		for i, v := range value.Content {
+			if v.Kind != yaml.ScalarNode {
+				return bounds{}, &yamlError{
+					line: v.Line,
+					err:  fmt.Errorf("bound should be a number, got: %s", v.Tag),
+				}
+			}
+			if i == 0 {
+				b.min = v.Value
+			} else {
+				b.max = v.Value
+			}
+		}
+	case yaml.MappingNode:
+		var aux struct {
+			Min          *string `yaml:"min"`
+			Max          *string `yaml:"max"`
+			ExclusiveMin bool    `yaml:"exclusiveMin"`
+			ExclusiveMax bool    `yaml:"exclusiveMax"`
+		}
+		if err := value.Decode(&aux); err != nil {
+			return bounds{}, err
+		}
+		b = bounds{
+			exclusiveMin: aux.ExclusiveMin,
+			exclusiveMax: aux.ExclusiveMax,
+		}
+		if aux.Min != nil {
+			b.min = *aux.Min
+		}
+		if aux.Max != nil {
+			b.max = *aux.Max
+		}
+	default:
+		return bounds{}, &yamlError{
+			line: value.Line,
+			err:  fmt.Errorf("range should be {max | [min, max] | \"[min, max)\" | {min, max, exclusiveMin, exclusiveMax}}, got: %s", value.Tag),
+		}
+	}
+	return b, nil
+}
+
+// parseInterval parses intervals like "[min, max)"
+func parseInterval(s string) (bounds, error) {
+	if len(s) < 2 || !strings.ContainsAny(s[len(s)-1:], "])") {
+		return bounds{}, fmt.Errorf("interval should end with \"]\" or \")\": %q", s)
+	}
+	parts := strings.Split(s[1:len(s)-1], ",")
+	if len(parts) != 2 {
+		return bounds{}, fmt.Errorf("interval should have 2 bounds: %q", s)
+	}
+	b := bounds{
+		min:          strings.TrimSpace(parts[0]),
+		max:          strings.TrimSpace(parts[1]),
+		exclusiveMin: s[0] == '(',
+		exclusiveMax: s[len(s)-1] == ')',
+	}
+	if b.min == "" || b.max == "" {
+		return bounds{}, errors.New("interval bounds should not be empty")
+	}
+	return b, nil
+}
@@ -0,0 +1,102 @@
+package schema
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BytesEncoding is an encoding of binary data in JSON
+type BytesEncoding string
+
+const (
+	// Base64Encoding is a string with standard base64
+	Base64Encoding BytesEncoding = "base64"
+	// Base64URLEncoding is a string with URL-safe base64
+	Base64URLEncoding BytesEncoding = "base64url"
+	// HexEncoding is a string with lowercase hexadecimal digits
+	HexEncoding BytesEncoding = "hex"
+	// ArrayEncoding is an array of byte numbers
+	ArrayEncoding BytesEncoding = "array"
+)
+
+var defaultBytesLength = Length{
+	Min: 1,
+	Max: 64,
+}
+
+// Bytes generates random binary data of Length bytes
+type Bytes struct {
+	Length   Length
+	Encoding BytesEncoding
+	// NoPadding omits padding of base64
+	NoPadding bool
+}
+
+func (b *Bytes) UnmarshalYAML(value *yaml.Node) error {
+	aux := struct {
+		Length   Length        `yaml:"length"`
+		Encoding BytesEncoding `yaml:"encoding"`
+		Padding  *bool         `yaml:"padding"`
+	}{
+		Length:   defaultBytesLength,
+		Encoding: Base64Encoding,
+	}
+	if err := value.Decode(&aux); err != nil {
+		return err
+	}
+	switch aux.Encoding {
+	case Base64Encoding, Base64URLEncoding:
+	case HexEncoding, ArrayEncoding:
+		if aux.Padding != nil {
+			return &yamlError{
+				line: value.Line,
+				err:  fmt.Errorf("padding is not supported by %q encoding", aux.Encoding),
+			}
+		}
+	default:
+		return &yamlError{
+			line: value.Line,
+			err:  fmt.Errorf("unsupported encoding: %q", aux.Encoding),
+		}
+	}
+	*b = Bytes{
+		Length:    aux.Length,
+		Encoding:  aux.Encoding,
+		NoPadding: aux.Padding != nil && !*aux.Padding,
+	}
+	return nil
+}
+
+func (b *Bytes) GenerateJSON(ctx *Context, w io.Writer, r *rand.Rand) error {
+	data := make([]byte, b.Length.Rand(r))
+	randBytes(r, data)
+	switch b.Encoding {
+	case ArrayEncoding:
+		buf := make([]byte, 0, 4*len(data)+2)
+		buf = append(buf, '[')
+		for i, c := range data {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = strconv.AppendUint(buf, uint64(c), 10)
+		}
+		_, err := w.Write(append(buf, ']'))
+		return err
+	case HexEncoding:
+		return ctx.writeString(w, []byte(hex.EncodeToString(data)))
+	}
+	enc := base64.StdEncoding
+	if b.Encoding == Base64URLEncoding {
+		enc = base64.URLEncoding
+	}
+	if b.NoPadding {
+		enc = enc.WithPadding(base64.NoPadding)
+	}
+	return ctx.writeString(w, []byte(enc.EncodeToString(data)))
+}
@@ -0,0 +1,116 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestBytes_GenerateJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		decode func(s string) ([]byte, error)
+	}{
+		{
+			name:   "base64",
+			data:   `{length: 10}`,
+			decode: base64.StdEncoding.DecodeString,
+		},
+		{
+			name:   "base64url without padding",
+			data:   `{length: 10, encoding: base64url, padding: false}`,
+			decode: base64.RawURLEncoding.DecodeString,
+		},
+		{
+			name:   "hex",
+			data:   `{length: 10, encoding: hex}`,
+			decode: hex.DecodeString,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b Bytes
+			require.NoError(t, yaml.Unmarshal([]byte(tt.data), &b))
+			var w bytes.Buffer
+			require.NoError(t, b.GenerateJSON(NewContext(), &w, rand.New(rand.NewSource(1))))
+			var s string
+			require.NoError(t, json.Unmarshal(w.Bytes(), &s))
+			data, err := tt.decode(s)
+			require.NoError(t, err)
+			assert.Len(t, data, 10)
+		})
+	}
+}
+
+func TestBytes_GenerateJSON_Array(t *testing.T) {
+	b := Bytes{
+		Length:   Length{Min: 3, Max: 3},
+		Encoding: ArrayEncoding,
+	}
+	var w bytes.Buffer
+	require.NoError(t, b.GenerateJSON(NewContext(), &w, rand.New(rand.NewSource(1))))
+	var data []int
+	require.NoError(t, json.Unmarshal(w.Bytes(), &data))
+	assert.Len(t, data, 3)
+	for _, c := range data {
+		assert.True(t, c >= 0 && c <= 255, c)
+	}
+}
+
+func TestBytes_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Bytes
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			data: `{}`,
+			want: Bytes{
+				Length:   defaultBytesLength,
+				Encoding: Base64Encoding,
+			},
+		},
+		{
+			name: "no padding",
+			data: `{encoding: base64url, padding: false}`,
+			want: Bytes{
+				Length:    defaultBytesLength,
+				Encoding:  Base64URLEncoding,
+				NoPadding: true,
+			},
+		},
+		{
+			name:    "unsupported encoding",
+			data:    `{encoding: base32}`,
+			wantErr: `unsupported encoding: "base32"`,
+		},
+		{
+			name:    "padding of hex",
+			data:    `{encoding: hex, padding: true}`,
+			wantErr: `padding is not supported by "hex" encoding`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Bytes
+			err := yaml.Unmarshal([]byte(tt.data), &got)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package schema
+
+import (
+	"math/rand"
+	"sort"
+	"unicode"
+)
+
+var defaultCharsetLength = Length{
+	Min: 1,
+	Max: 10,
+}
+
+// charsets are named sets of characters
+var charsets = map[string]runeClass{
+	"alpha":     newRuneClass([]rune{'A', 'Z', 'a', 'z'}),
+	"alnum":     newRuneClass([]rune{'0', '9', 'A', 'Z', 'a', 'z'}),
+	"digits":    newRuneClass([]rune{'0', '9'}),
+	"lower":     newRuneClass([]rune{'a', 'z'}),
+	"upper":     newRuneClass([]rune{'A', 'Z'}),
+	"hex":       newRuneClass([]rune{'0', '9', 'a', 'f'}),
+	"base64url": newRuneClass([]rune{'-', '-', '0', '9', 'A', 'Z', '_', '_', 'a', 'z'}),
+	"ascii":     newRuneClass([]rune{' ', '~'}),
+	"letters":   newRuneClassFromTable(unicode.L),
+}
+
+// StringCharset generates strings of random length from a set of characters
+type StringCharset struct {
+	Length Length
+	class  runeClass
+}
+
+// NewStringCharset returns StringCharset for named charset
+func NewStringCharset(name string, length Length) (*StringCharset, bool) {
+	class, found := charsets[name]
+	if !found {
+		return nil, false
+	}
+	return &StringCharset{
+		Length: length,
+		class:  class,
+	}, true
+}
+
+// NewStringChars returns StringCharset for given characters
+func NewStringChars(chars string, length Length) *StringCharset {
+	runes := []rune(chars)
+	sort.Slice(runes, func(i, j int) bool {
+		return runes[i] < runes[j]
+	})
+	pairs := make([]rune, 0, 2*len(runes))
+	for i, c := range runes {
+		if i > 0 && c == runes[i-1] {
+			continue
+		}
+		pairs = append(pairs, c, c)
+	}
+	return &StringCharset{
+		Length: length,
+		class:  newRuneClass(pairs),
+	}
+}
+
+func (s *StringCharset) Rand(_ *Context, r *rand.Rand) ([]byte, error) {
+	n := s.Length.Rand(r)
+	b := make([]byte, 0, n)
+	for i := uint64(0); i < n; i++ {
+		b = appendRune(b, s.class.Rand(r))
+	}
+	return b, nil
+}
+
+// newRuneClassFromTable returns runeClass with runes from table
+func newRuneClassFromTable(table *unicode.RangeTable) runeClass {
+	var pairs []rune
+	add := func(lo, hi, stride rune) {
+		if stride == 1 {
+			pairs = append(pairs, lo, hi)
+			return
+		}
+		for c := lo; c <= hi; c += stride {
+			pairs = append(pairs, c, c)
+		}
+	}
+	for _, r := range table.R16 {
+		add(rune(r.Lo), rune(r.Hi), rune(r.Stride))
+	}
+	for _, r := range table.R32 {
+		add(rune(r.Lo), rune(r.Hi), rune(r.Stride))
+	}
+	return newRuneClass(pairs)
+}
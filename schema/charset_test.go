@@ -0,0 +1,72 @@
+package schema
+
+import (
+	"math/rand"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestString_UnmarshalYAML_Charset(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		check   func(rune) bool
+		wantErr bool
+	}{
+		{
+			name: "alnum",
+			data: "{charset: alnum, length: [8, 32]}",
+			check: func(c rune) bool {
+				return c < unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c))
+			},
+		},
+		{
+			name:  "letters",
+			data:  "{charset: letters, length: 20}",
+			check: unicode.IsLetter,
+		},
+		{
+			name: "chars",
+			data: "{chars: xyz, length: 20}",
+			check: func(c rune) bool {
+				return c == 'x' || c == 'y' || c == 'z'
+			},
+		},
+		{
+			name:    "unknown charset",
+			data:    "{charset: unknown}",
+			wantErr: true,
+		},
+		{
+			name:    "charset and chars",
+			data:    "{charset: hex, chars: abc}",
+			wantErr: true,
+		},
+	}
+	r := rand.New(rand.NewSource(1))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s String
+			err := yaml.Unmarshal([]byte(tt.data), &s)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			cs := s.StringRander.(*StringCharset)
+			for i := 0; i < 100; i++ {
+				b, err := s.Rand(nil, r)
+				require.NoError(t, err)
+				runes := []rune(string(b))
+				assert.True(t, uint64(len(runes)) >= cs.Length.Min && uint64(len(runes)) <= cs.Length.Max)
+				for _, c := range runes {
+					assert.True(t, tt.check(c), "unexpected %q", c)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Const always generates the same value
+type Const struct {
+	// JSON is the value encoded in JSON with default StringEncoder
+	JSON []byte
+	// value is kept to encode it with StringEncoder other than default
+	value *yaml.Node
+}
+
+func (c *Const) UnmarshalYAML(value *yaml.Node) error {
+	var aux struct {
+		Value yaml.Node `yaml:"value"`
+	}
+	if err := value.Decode(&aux); err != nil {
+		return err
+	}
+	if aux.Value.Kind == 0 {
+		return &yamlError{
+			line: value.Line,
+			err:  errors.New("\"value\" is required"),
+		}
+	}
+	var b bytes.Buffer
+	if err := writeYAMLAsJSON(&b, &StringEncoder{}, &aux.Value); err != nil {
+		return err
+	}
+	*c = Const{
+		JSON:  b.Bytes(),
+		value: &aux.Value,
+	}
+	return nil
+}
+
+func (c *Const) GenerateJSON(ctx *Context, w io.Writer, _ *rand.Rand) error {
+	if c.value == nil || ctx.StringEncoder() == (StringEncoder{}) {
+		_, err := w.Write(c.JSON)
+		return err
+	}
+	var b bytes.Buffer
+	enc := ctx.StringEncoder()
+	if err := writeYAMLAsJSON(&b, &enc, c.value); err != nil {
+		return err
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// writeYAMLAsJSON converts YAML value to JSON keeping the order of keys
+func writeYAMLAsJSON(b *bytes.Buffer, enc *StringEncoder, value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.DocumentNode:
+		if len(value.Content) == 0 {
+			b.Write(nullJSON)
+			return nil
+		}
+		return writeYAMLAsJSON(b, enc, value.Content[0])
+	case yaml.AliasNode:
+		return writeYAMLAsJSON(b, enc, value.Alias)
+	case yaml.ScalarNode:
+		return writeYAMLScalarAsJSON(b, enc, value)
+	case yaml.SequenceNode:
+		b.WriteByte('[')
+		for i, v := range value.Content {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := writeYAMLAsJSON(b, enc, v); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+		return nil
+	case yaml.MappingNode:
+		b.WriteByte('{')
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			k, v := value.Content[i], value.Content[i+1]
+			if k.Kind != yaml.ScalarNode {
+				return &yamlError{
+					line: k.Line,
+					err:  fmt.Errorf("keys of mapping should be scalars, got: %s", k.Tag),
+				}
+			}
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			key, err := enc.Append(nil, []byte(k.Value))
+			if err != nil {
+				return &yamlError{
+					line: k.Line,
+					err:  err,
+				}
+			}
+			b.Write(key)
+			b.WriteByte(':')
+			if err := writeYAMLAsJSON(b, enc, v); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+		return nil
+	}
+	return &yamlError{
+		line: value.Line,
+		err:  fmt.Errorf("unsupported value: %s", value.Tag),
+	}
+}
+
+func writeYAMLScalarAsJSON(b *bytes.Buffer, enc *StringEncoder, value *yaml.Node) error {
+	var (
+		j   []byte
+		err error
+	)
+	switch value.ShortTag() {
+	case "!!null", "!!bool", "!!int", "!!float":
+		var v interface{}
+		if err := value.Decode(&v); err != nil {
+			return err
+		}
+		j, err = json.Marshal(v)
+	default:
+		j, err = enc.Append(nil, []byte(value.Value))
+	}
+	if err != nil {
+		return &yamlError{
+			line: value.Line,
+			err:  err,
+		}
+	}
+	b.Write(j)
+	return nil
+}
+
+func (c *Const) Count(*Context) (uint64, bool) {
+	return 1, true
+}
+
+func (c *Const) Enumerate(ctx *Context) ([][]byte, bool, error) {
+	var b bytes.Buffer
+	if err := c.GenerateJSON(ctx, &b, nil); err != nil {
+		return nil, false, err
+	}
+	return [][]byte{b.Bytes()}, true, nil
+}
@@ -0,0 +1,86 @@
+package schema
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestConst_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		wantJSON string
+		wantErr  bool
+	}{
+		{
+			name:     "int",
+			data:     "value: 2",
+			wantJSON: "2",
+		},
+		{
+			name:     "string",
+			data:     "value: Event",
+			wantJSON: `"Event"`,
+		},
+		{
+			name:     "quoted number",
+			data:     `value: "2"`,
+			wantJSON: `"2"`,
+		},
+		{
+			name:     "null",
+			data:     "value: null",
+			wantJSON: "null",
+		},
+		{
+			name:     "sequence",
+			data:     "value: [1, 2.5, true, a]",
+			wantJSON: `[1,2.5,true,"a"]`,
+		},
+		{
+			name:     "mapping keeps order",
+			data:     "value: {b: 1, a: [x], 3: {}}",
+			wantJSON: `{"b":1,"a":["x"],"3":{}}`,
+		},
+		{
+			name:    "no value",
+			data:    "type: const",
+			wantErr: true,
+		},
+		{
+			name:    "infinity",
+			data:    "value: .inf",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c Const
+			err := yaml.Unmarshal([]byte(tt.data), &c)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantJSON, string(c.JSON))
+		})
+	}
+}
+
+func TestNull_inline(t *testing.T) {
+	var o Object
+	require.NoError(t, yaml.Unmarshal([]byte(`
+fields:
+  inline: null
+  explicit:
+    type: null
+`), &o))
+	var w bytes.Buffer
+	ctx := NewContext()
+	ctx.SetSortKeys(true)
+	require.NoError(t, o.GenerateJSON(ctx, &w, nil))
+	require.Equal(t, `{"explicit":null,"inline":null}`, w.String())
+}
@@ -1,19 +1,42 @@
 package schema
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math/rand"
+	"time"
 )
 
 type Context struct {
-	sortKeys bool
-	files    map[string]LineSource
+	sortKeys      bool
+	stringEncoder StringEncoder
+	// now is pinned, so the output depends only on seed
+	now   time.Time
+	files map[string]LineSource
+	// refDepths are current depths of definitions expanded by Ref
+	refDepths map[string]uint
+	// sequences are next values of sequences
+	sequences map[*Sequence]int64
+	// scopes are scopes of objects being generated, innermost last
+	scopes []scope
+	// markovChains are Markov chains trained on files
+	markovChains map[markovChainKey]*markovChain
+	// ids is a state of time-ordered identifiers
+	ids idState
+	// distinctLines are distinct lines of files
+	distinctLines map[string]*fileLines
 }
 
 func NewContext() *Context {
 	return &Context{
-		files: make(map[string]LineSource),
+		now:           time.Now(),
+		files:         make(map[string]LineSource),
+		refDepths:     make(map[string]uint),
+		sequences:     make(map[*Sequence]int64),
+		markovChains:  make(map[markovChainKey]*markovChain),
+		distinctLines: make(map[string]*fileLines),
 	}
 }
 
@@ -25,6 +48,75 @@ func (c *Context) SortKeys() bool {
 	return c.sortKeys
 }
 
+// nextInSequence returns the next value of s and advances it
+func (c *Context) nextInSequence(s *Sequence) int64 {
+	num, ok := c.sequences[s]
+	if !ok {
+		num = s.Start
+	}
+	c.sequences[s] = num + s.Step
+	return num
+}
+
+func (c *Context) pushScope(s scope) {
+	c.scopes = append(c.scopes, s)
+}
+
+func (c *Context) popScope() {
+	c.scopes = c.scopes[:len(c.scopes)-1]
+}
+
+// fieldValue returns JSON value of the field referred by p.
+// It returns nil if the field was not generated.
+func (c *Context) fieldValue(p RefPath) (json.RawMessage, error) {
+	i := len(c.scopes) - p.Up
+	if i < 0 {
+		return nil, fmt.Errorf("%s: reference goes beyond root", p)
+	}
+	v := c.scopes[i][p.Names[0]]
+	for _, name := range p.Names[1:] {
+		if v == nil {
+			return nil, nil
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(v, &fields); err != nil {
+			return nil, fmt.Errorf("%s: %q is not in an object", p, name)
+		}
+		v = fields[name]
+	}
+	if bytes.Equal(v, nullJSON) {
+		return nil, nil
+	}
+	return v, nil
+}
+
+// SetNow sets the time which relative times are relative to
+func (c *Context) SetNow(t time.Time) {
+	c.now = t
+}
+
+func (c *Context) Now() time.Time {
+	return c.now
+}
+
+func (c *Context) SetStringEncoder(e StringEncoder) {
+	c.stringEncoder = e
+}
+
+func (c *Context) StringEncoder() StringEncoder {
+	return c.stringEncoder
+}
+
+// writeString writes s encoded as JSON string to w
+func (c *Context) writeString(w io.Writer, s []byte) error {
+	b, err := c.stringEncoder.Append(make([]byte, 0, len(s)+2), s)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
 func (c *Context) AddFile(name string, file string) error {
 	f, err := ScanFile(file)
 	if err != nil {
@@ -42,6 +134,60 @@ func (c *Context) Rand(r *rand.Rand, name string) ([]byte, error) {
 	return f.Rand(r)
 }
 
+func (c *Context) hasFile(name string) bool {
+	_, found := c.files[name]
+	return found
+}
+
+// Lines returns the number of lines in file
+func (c *Context) Lines(name string) (int, error) {
+	f, ok := c.files[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown file %q", name)
+	}
+	return f.Len(), nil
+}
+
+// markovChain returns Markov chain of given order trained on the file.
+// It is trained once and cached for the following calls.
+func (c *Context) markovChain(name string, order int) (*markovChain, error) {
+	key := markovChainKey{
+		file:  name,
+		order: order,
+	}
+	if chain, ok := c.markovChains[key]; ok {
+		return chain, nil
+	}
+	f, ok := c.files[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown file %q", name)
+	}
+	chain, err := newMarkovChain(f, order)
+	if err != nil {
+		return nil, fmt.Errorf("markov %q: %w", name, err)
+	}
+	c.markovChains[key] = chain
+	return chain, nil
+}
+
+// fileLines returns distinct lines of file.
+// They are found once and cached for the following calls.
+func (c *Context) fileLines(name string) (*fileLines, error) {
+	if l, ok := c.distinctLines[name]; ok {
+		return l, nil
+	}
+	f, ok := c.files[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown file %q", name)
+	}
+	l, err := newFileLines(f)
+	if err != nil {
+		return nil, err
+	}
+	c.distinctLines[name] = l
+	return l, nil
+}
+
 func (c *Context) Close() error {
 	var errs Errors
 	for _, f := range c.files {
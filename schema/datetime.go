@@ -0,0 +1,373 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TimeFormat is a format of DateTime output
+type TimeFormat string
+
+const (
+	RFC3339Format     TimeFormat = "rfc3339"
+	RFC3339NanoFormat TimeFormat = "rfc3339nano"
+	DateFormat        TimeFormat = "date"
+	// UnixFormat is a number of seconds since Unix epoch
+	UnixFormat TimeFormat = "unix"
+	// UnixMilliFormat is a number of milliseconds since Unix epoch
+	UnixMilliFormat TimeFormat = "unixmilli"
+	// DurationFormat is ISO 8601 duration since the start of range
+	DurationFormat TimeFormat = "duration"
+)
+
+var defaultTimeRange = TimeRange{
+	Start: TimeExpr{
+		Relative: true,
+		Offset:   -365 * day,
+	},
+	End: TimeExpr{
+		Relative: true,
+	},
+}
+
+// DateTime generates dates and times
+type DateTime struct {
+	Range  TimeRange
+	Format TimeFormat
+	// Layout is a layout of time package used instead of Format if it is set
+	Layout string
+	// Locations are time zones to choose from, nil means UTC
+	Locations []*time.Location
+}
+
+func (d *DateTime) UnmarshalYAML(value *yaml.Node) error {
+	aux := struct {
+		Range    TimeRange  `yaml:"range"`
+		Format   TimeFormat `yaml:"format"`
+		Layout   string     `yaml:"layout"`
+		Timezone yaml.Node  `yaml:"timezone"`
+	}{
+		Range: defaultTimeRange,
+	}
+	if err := value.Decode(&aux); err != nil {
+		return err
+	}
+	*d = DateTime{
+		Range:  aux.Range,
+		Format: aux.Format,
+		Layout: aux.Layout,
+	}
+	switch d.Format {
+	case "":
+		if d.Layout == "" {
+			d.Format = RFC3339Format
+		}
+	case RFC3339Format, RFC3339NanoFormat, DateFormat, UnixFormat, UnixMilliFormat, DurationFormat:
+		if d.Layout != "" {
+			return &yamlError{
+				line: value.Line,
+				err:  errors.New("datetime should have either format or layout, not both"),
+			}
+		}
+	default:
+		return &yamlError{
+			line: value.Line,
+			err:  fmt.Errorf("unsupported format: %q, use \"layout\" for custom layouts", d.Format),
+		}
+	}
+
+	var zones []string
+	switch aux.Timezone.Kind {
+	case 0:
+	case yaml.ScalarNode:
+		zones = []string{aux.Timezone.Value}
+	default:
+		if err := aux.Timezone.Decode(&zones); err != nil {
+			return err
+		}
+	}
+	for _, zone := range zones {
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			return &yamlError{
+				line: aux.Timezone.Line,
+				err:  err,
+			}
+		}
+		d.Locations = append(d.Locations, loc)
+	}
+	return nil
+}
+
+func (d *DateTime) GenerateJSON(ctx *Context, w io.Writer, r *rand.Rand) error {
+	start, end := d.Range.Start.Time(ctx.Now()), d.Range.End.Time(ctx.Now())
+	if end.Before(start) {
+		return fmt.Errorf("start of range (%s) is after its end (%s)", start, end)
+	}
+	t := randTime(r, start, end)
+	if l := len(d.Locations); l > 0 {
+		t = t.In(d.Locations[r.Intn(l)])
+	} else {
+		t = t.UTC()
+	}
+
+	switch d.Format {
+	case UnixFormat:
+		_, err := w.Write([]byte(strconv.FormatInt(t.Unix(), 10)))
+		return err
+	case UnixMilliFormat:
+		_, err := w.Write([]byte(strconv.FormatInt(unixMilli(t), 10)))
+		return err
+	case DurationFormat:
+		return ctx.writeString(w, []byte(formatISODuration(t.Sub(start))))
+	}
+	layout := d.Layout
+	if layout == "" {
+		layout = d.Format.layout()
+	}
+	return ctx.writeString(w, []byte(t.Format(layout)))
+}
+
+// epoch returns unit of numeric formats, zero for others
+func (f TimeFormat) epoch() time.Duration {
+	switch f {
+	case UnixFormat:
+		return time.Second
+	case UnixMilliFormat:
+		return time.Millisecond
+	}
+	return 0
+}
+
+// layout returns layout of time package for formats of strings
+func (f TimeFormat) layout() string {
+	switch f {
+	case RFC3339NanoFormat:
+		return time.RFC3339Nano
+	case DateFormat:
+		return dateLayout
+	}
+	return time.RFC3339
+}
+
+const dateLayout = "2006-01-02"
+
+// unixMilli returns Unix time of t in milliseconds.
+// Unlike UnixNano, it does not overflow for years out of [1678, 2262].
+func unixMilli(t time.Time) int64 {
+	return t.Unix()*1e3 + int64(t.Nanosecond())/1e6
+}
+
+// randTime returns uniformly random time in [start, end]
+func randTime(r *rand.Rand, start, end time.Time) time.Time {
+	if d := end.Sub(start); d < math.MaxInt64 {
+		return start.Add(time.Duration(r.Int63n(int64(d) + 1)))
+	}
+	// nanoseconds of range overflow int64, so random second of range
+	// and random nanosecond of that second are generated separately.
+	// They can give time after end, which is rejected.
+	secs := end.Unix() - start.Unix()
+	for {
+		t := time.Unix(start.Unix()+r.Int63n(secs+1), int64(start.Nanosecond())+r.Int63n(int64(time.Second)))
+		if !t.After(end) {
+			return t
+		}
+	}
+}
+
+// TimeRange is an inclusive range of time
+type TimeRange struct {
+	Start, End TimeExpr
+}
+
+func (r *TimeRange) UnmarshalYAML(value *yaml.Node) error {
+	var aux [2]TimeExpr
+	if err := value.Decode(&aux); err != nil {
+		return err
+	}
+	r.Start, r.End = aux[0], aux[1]
+	if r.Start.Relative == r.End.Relative && r.End.Time(time.Time{}).Before(r.Start.Time(time.Time{})) {
+		return &yamlError{
+			line: value.Line,
+			err:  errors.New("start should be before end"),
+		}
+	}
+	return nil
+}
+
+// TimeExpr is either an absolute time or a time relative to now
+type TimeExpr struct {
+	// Relative is true if time is relative to now
+	Relative bool
+	// Absolute time is used if Relative is false
+	Absolute time.Time
+	// Offset is added to now if Relative is true
+	Offset time.Duration
+}
+
+// Time returns time which expression refers to
+func (e TimeExpr) Time(now time.Time) time.Time {
+	if e.Relative {
+		return now.Add(e.Offset)
+	}
+	return e.Absolute
+}
+
+// ParseTimeExpr parses RFC 3339 time, date in form of "2006-01-02",
+// "now" or time relative to now (e.g. "now-30d" or "now+1h30m")
+func ParseTimeExpr(s string) (TimeExpr, error) {
+	if strings.HasPrefix(s, "now") {
+		e := TimeExpr{
+			Relative: true,
+		}
+		switch offset := s[len("now"):]; {
+		case offset == "":
+			return e, nil
+		case offset[0] == '+' || offset[0] == '-':
+			d, err := ParseDuration(offset)
+			if err != nil {
+				return TimeExpr{}, err
+			}
+			e.Offset = d
+			return e, nil
+		}
+		return TimeExpr{}, fmt.Errorf("invalid time: %q", s)
+	}
+	for _, layout := range []string{time.RFC3339Nano, dateLayout} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return TimeExpr{
+				Absolute: t,
+			}, nil
+		}
+	}
+	return TimeExpr{}, fmt.Errorf("time should be RFC 3339, date or relative to now, got: %q", s)
+}
+
+func (e *TimeExpr) UnmarshalYAML(value *yaml.Node) error {
+	var s string
+	if err := value.Decode(&s); err != nil {
+		return err
+	}
+	var err error
+	*e, err = ParseTimeExpr(s)
+	if err != nil {
+		return &yamlError{
+			line: value.Line,
+			err:  err,
+		}
+	}
+	return nil
+}
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+	year = 365 * day
+)
+
+var durationUnits = map[string]time.Duration{
+	"d": day,
+	"w": week,
+	"y": year,
+}
+
+// ParseDuration is like time.ParseDuration, but it also
+// supports days ("d"), weeks ("w") and years of 365 days ("y")
+func ParseDuration(s string) (time.Duration, error) {
+	orig := s
+	var neg bool
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	if s == "" {
+		return 0, fmt.Errorf("invalid duration: %q", orig)
+	}
+	var d time.Duration
+	for s != "" {
+		i := strings.IndexFunc(s, func(c rune) bool {
+			return (c < '0' || c > '9') && c != '.'
+		})
+		if i <= 0 {
+			return 0, fmt.Errorf("invalid duration: %q", orig)
+		}
+		j := i + strings.IndexFunc(s[i:], func(c rune) bool {
+			return (c >= '0' && c <= '9') || c == '.'
+		})
+		if j < i {
+			j = len(s)
+		}
+		num, unit := s[:i], s[i:j]
+		var term time.Duration
+		if u, found := durationUnits[unit]; found {
+			f, err := strconv.ParseFloat(num, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration: %q", orig)
+			}
+			// float64(math.MaxInt64) is rounded up to 2^63
+			if f*float64(u) >= math.MaxInt64 {
+				return 0, fmt.Errorf("duration is out of range: %q", orig)
+			}
+			term = time.Duration(f * float64(u))
+		} else {
+			pd, err := time.ParseDuration(s[:j])
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration: %q", orig)
+			}
+			term = pd
+		}
+		// terms are not negative
+		if d > math.MaxInt64-term {
+			return 0, fmt.Errorf("duration is out of range: %q", orig)
+		}
+		d += term
+		s = s[j:]
+	}
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// formatISODuration formats d as ISO 8601 duration, e.g. "P1DT2H3M4.5S".
+// Days are the largest unit, since months and years have different lengths.
+func formatISODuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+	var b strings.Builder
+	if d < 0 {
+		b.WriteByte('-')
+		d = -d
+	}
+	b.WriteByte('P')
+	if days := d / day; days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+		d -= days * day
+	}
+	if d == 0 {
+		return b.String()
+	}
+	b.WriteByte('T')
+	if h := d / time.Hour; h > 0 {
+		fmt.Fprintf(&b, "%dH", h)
+		d -= h * time.Hour
+	}
+	if m := d / time.Minute; m > 0 {
+		fmt.Fprintf(&b, "%dM", m)
+		d -= m * time.Minute
+	}
+	if d > 0 {
+		b.WriteString(strconv.FormatFloat(d.Seconds(), 'f', -1, 64))
+		b.WriteByte('S')
+	}
+	return b.String()
+}
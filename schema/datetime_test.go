@@ -0,0 +1,184 @@
+package schema
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    time.Duration
+		wantErr bool
+	}{
+		{s: "30d", want: 30 * day},
+		{s: "-1w", want: -week},
+		{s: "+1y2d", want: year + 2*day},
+		{s: "1h30m", want: time.Hour + 30*time.Minute},
+		{s: "1.5d12h", want: 2 * day},
+		{s: "100ms", want: 100 * time.Millisecond},
+		{s: "", wantErr: true},
+		{s: "-", wantErr: true},
+		{s: "d", wantErr: true},
+		{s: "1x", wantErr: true},
+		{s: "300y", wantErr: true},
+		{s: "-300y", wantErr: true},
+		{s: "200y100y", wantErr: true},
+		{s: "200y2562047h", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			d, err := ParseDuration(tt.s)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, d)
+		})
+	}
+}
+
+func TestFormatISODuration(t *testing.T) {
+	require.Equal(t, "PT0S", formatISODuration(0))
+	require.Equal(t, "P1DT2H3M4.5S", formatISODuration(day+2*time.Hour+3*time.Minute+4500*time.Millisecond))
+	require.Equal(t, "-P2D", formatISODuration(-2*day))
+}
+
+func TestDateTime_GenerateJSON(t *testing.T) {
+	now := time.Date(2020, 3, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		data  string
+		wantW string
+	}{
+		{
+			name:  "rfc3339",
+			data:  "range: [now-1h, now-1h]",
+			wantW: `"2020-03-15T11:00:00Z"`,
+		},
+		{
+			name:  "date",
+			data:  "{range: [2020-01-02, 2020-01-02], format: date}",
+			wantW: `"2020-01-02"`,
+		},
+		{
+			name:  "unix",
+			data:  "{range: [now, now], format: unix}",
+			wantW: "1584273600",
+		},
+		{
+			name:  "unixmilli",
+			data:  "{range: [now, now], format: unixmilli}",
+			wantW: "1584273600000",
+		},
+		{
+			name:  "unixmilli out of unixnano range",
+			data:  "{range: [0001-01-01T00:00:00.5Z, 0001-01-01T00:00:00.5Z], format: unixmilli}",
+			wantW: "-62135596799500",
+		},
+		{
+			name:  "layout and timezone",
+			data:  "{range: [now, now], layout: '02 Jan 06 15:04 MST', timezone: Europe/Moscow}",
+			wantW: `"15 Mar 20 15:00 MSK"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d DateTime
+			require.NoError(t, yaml.Unmarshal([]byte(tt.data), &d))
+			ctx := NewContext()
+			ctx.SetNow(now)
+			var w bytes.Buffer
+			require.NoError(t, d.GenerateJSON(ctx, &w, rand.New(rand.NewSource(1))))
+			require.Equal(t, tt.wantW, w.String())
+		})
+	}
+}
+
+func TestDateTime_UnmarshalYAML_Format(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    DateTime
+		wantErr string
+	}{
+		{
+			name: "default",
+			data: "{}",
+			want: DateTime{Range: defaultTimeRange, Format: RFC3339Format},
+		},
+		{
+			name: "layout",
+			data: "{layout: '02 Jan 06'}",
+			want: DateTime{Range: defaultTimeRange, Layout: "02 Jan 06"},
+		},
+		{
+			name:    "unknown format",
+			data:    "{format: rfc3399}",
+			wantErr: `unsupported format: "rfc3399"`,
+		},
+		{
+			name:    "format and layout",
+			data:    "{format: date, layout: '02 Jan 06'}",
+			wantErr: "datetime should have either format or layout, not both",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d DateTime
+			err := yaml.Unmarshal([]byte(tt.data), &d)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, d)
+		})
+	}
+}
+
+func TestRandTime(t *testing.T) {
+	start := time.Date(2020, 3, 15, 12, 0, 0, 300000000, time.UTC)
+	end := start.Add(time.Second)
+	r := rand.New(rand.NewSource(1))
+	var bounds int
+	for i := 0; i < 1000; i++ {
+		got := randTime(r, start, end)
+		require.False(t, got.Before(start) || got.After(end), got)
+		if got.Equal(start) || got.Equal(end) {
+			bounds++
+		}
+	}
+	require.Less(t, bounds, 5)
+
+	// nanoseconds of range overflow int64
+	start, end = time.Date(1000, 1, 1, 0, 0, 0, 500, time.UTC), time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 1000; i++ {
+		got := randTime(r, start, end)
+		require.False(t, got.Before(start) || got.After(end), got)
+	}
+}
+
+func TestDateTime_GenerateJSON_Range(t *testing.T) {
+	var d DateTime
+	require.NoError(t, yaml.Unmarshal([]byte("{range: [now-30d, now+1d], format: rfc3339nano}"), &d))
+	now := time.Date(2020, 3, 15, 12, 0, 0, 0, time.UTC)
+	ctx := NewContext()
+	ctx.SetNow(now)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		var w bytes.Buffer
+		require.NoError(t, d.GenerateJSON(ctx, &w, r))
+		got, err := time.Parse(`"`+time.RFC3339Nano+`"`, w.String())
+		require.NoError(t, err)
+		require.False(t, got.Before(now.Add(-30*day)))
+		require.False(t, got.After(now.Add(day)))
+	}
+}
@@ -0,0 +1,273 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Distribution is a distribution of random numbers
+type Distribution interface {
+	// Rand returns a random number. Range [min, max] is given to
+	// distributions defined relative to it, the result is not clamped.
+	Rand(r *rand.Rand, min, max float64) float64
+}
+
+type distributionType string
+
+const (
+	normalDistribution      distributionType = "normal"
+	logNormalDistribution   distributionType = "lognormal"
+	exponentialDistribution distributionType = "exponential"
+	poissonDistribution     distributionType = "poisson"
+	zipfDistribution        distributionType = "zipf"
+	histogramDistribution   distributionType = "histogram"
+)
+
+// distribution is a helper type for unmarshal Distribution
+type distribution struct {
+	Distribution
+}
+
+func (d *distribution) UnmarshalYAML(value *yaml.Node) error {
+	var aux struct {
+		Type distributionType `yaml:"type"`
+	}
+	if err := value.Decode(&aux); err != nil {
+		return err
+	}
+	var err error
+	switch aux.Type {
+	case normalDistribution:
+		var n Normal
+		err = value.Decode(&n)
+		d.Distribution = n
+	case logNormalDistribution:
+		var n LogNormal
+		err = value.Decode(&n)
+		d.Distribution = n
+	case exponentialDistribution:
+		e := Exponential{Rate: 1}
+		err = value.Decode(&e)
+		d.Distribution = e
+	case poissonDistribution:
+		var p Poisson
+		err = value.Decode(&p)
+		d.Distribution = p
+	case zipfDistribution:
+		z := Zipf{V: 1}
+		err = value.Decode(&z)
+		d.Distribution = &z
+	case histogramDistribution:
+		h := &Histogram{}
+		err = value.Decode(h)
+		d.Distribution = h
+	case "":
+		return &yamlError{
+			line: value.Line,
+			err:  errors.New("distribution type is required"),
+		}
+	default:
+		return &yamlError{
+			line: value.Line,
+			err:  fmt.Errorf("unsupported distribution: %q", aux.Type),
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if v, ok := d.Distribution.(interface{ validate() error }); ok {
+		if err := v.validate(); err != nil {
+			return &yamlError{
+				line: value.Line,
+				err:  fmt.Errorf("%s: %w", aux.Type, err),
+			}
+		}
+	}
+	return nil
+}
+
+// Normal is a normal distribution
+type Normal struct {
+	Mean   float64 `yaml:"mean"`
+	StdDev float64 `yaml:"stddev"`
+}
+
+func (n Normal) Rand(r *rand.Rand, _, _ float64) float64 {
+	return n.Mean + n.StdDev*r.NormFloat64()
+}
+
+func (n Normal) validate() error {
+	if n.StdDev <= 0 {
+		return errors.New("stddev should be positive")
+	}
+	return nil
+}
+
+// LogNormal is a distribution of numbers which logarithms are normally distributed
+type LogNormal struct {
+	Mu    float64 `yaml:"mu"`
+	Sigma float64 `yaml:"sigma"`
+}
+
+func (n LogNormal) Rand(r *rand.Rand, _, _ float64) float64 {
+	return math.Exp(n.Mu + n.Sigma*r.NormFloat64())
+}
+
+func (n LogNormal) validate() error {
+	if n.Sigma <= 0 {
+		return errors.New("sigma should be positive")
+	}
+	return nil
+}
+
+// Exponential is an exponential distribution shifted to min
+type Exponential struct {
+	Rate float64 `yaml:"rate"`
+}
+
+func (e Exponential) Rand(r *rand.Rand, min, _ float64) float64 {
+	return min + r.ExpFloat64()/e.Rate
+}
+
+func (e Exponential) validate() error {
+	if e.Rate <= 0 {
+		return errors.New("rate should be positive")
+	}
+	return nil
+}
+
+// Poisson is a Poisson distribution
+type Poisson struct {
+	Lambda float64 `yaml:"lambda"`
+}
+
+// poissonNormalThreshold is lambda above which
+// Poisson distribution is approximated by normal
+const poissonNormalThreshold = 30
+
+func (p Poisson) Rand(r *rand.Rand, _, _ float64) float64 {
+	if p.Lambda > poissonNormalThreshold {
+		return math.Max(0, math.Round(p.Lambda+math.Sqrt(p.Lambda)*r.NormFloat64()))
+	}
+	// Knuth's algorithm
+	l := math.Exp(-p.Lambda)
+	k, prod := 0.0, r.Float64()
+	for prod > l {
+		k++
+		prod *= r.Float64()
+	}
+	return k
+}
+
+func (p Poisson) validate() error {
+	if p.Lambda <= 0 {
+		return errors.New("lambda should be positive")
+	}
+	return nil
+}
+
+// Zipf is a Zipf distribution of min + k, where probability of k
+// is proportional to (V + k)^(-S). It is like rand.Zipf, but draws
+// from the given *rand.Rand on each call.
+type Zipf struct {
+	S float64 `yaml:"s"`
+	V float64 `yaml:"v"`
+}
+
+func (z *Zipf) Rand(r *rand.Rand, min, max float64) float64 {
+	var (
+		q            = z.S
+		oneminusQ    = 1 - q
+		oneminusQinv = 1 / oneminusQ
+		h            = func(x float64) float64 {
+			return math.Exp(oneminusQ*math.Log(z.V+x)) * oneminusQinv
+		}
+		hinv = func(x float64) float64 {
+			return math.Exp(oneminusQinv*math.Log(oneminusQ*x)) - z.V
+		}
+		hxm         = h(max - min + 0.5)
+		hx0minusHxm = h(0.5) - math.Exp(math.Log(z.V)*(-q)) - hxm
+		s           = 1 - hinv(h(1.5)-math.Exp(-q*math.Log(z.V+1)))
+	)
+	// rejection-inversion by W.Hormann and G.Derflinger
+	for {
+		ur := hxm + r.Float64()*hx0minusHxm
+		x := hinv(ur)
+		k := math.Floor(x + 0.5)
+		if k-x <= s || ur >= h(k+0.5)-math.Exp(-math.Log(k+z.V)*q) {
+			return min + k
+		}
+	}
+}
+
+func (z *Zipf) validate() error {
+	if z.S <= 1 {
+		return errors.New("s should be greater than 1")
+	}
+	if z.V < 1 {
+		return errors.New("v should not be less than 1")
+	}
+	return nil
+}
+
+// Histogram is a piecewise uniform distribution
+type Histogram struct {
+	Bins    []FloatRange
+	weights *weights
+}
+
+func (h *Histogram) UnmarshalYAML(value *yaml.Node) error {
+	var aux struct {
+		Bins []struct {
+			Range  [2]float64 `yaml:"range"`
+			Weight *float64   `yaml:"weight"`
+		} `yaml:"bins"`
+	}
+	if err := value.Decode(&aux); err != nil {
+		return err
+	}
+	if len(aux.Bins) == 0 {
+		return &yamlError{
+			line: value.Line,
+			err:  errors.New("histogram: bins are required"),
+		}
+	}
+	*h = Histogram{
+		Bins: make([]FloatRange, 0, len(aux.Bins)),
+	}
+	ws := make([]float64, 0, len(aux.Bins))
+	for i, b := range aux.Bins {
+		if b.Range[0] > b.Range[1] {
+			return &yamlError{
+				line: value.Line,
+				err:  fmt.Errorf("histogram: bin %d: min should not be greater than max", i),
+			}
+		}
+		h.Bins = append(h.Bins, FloatRange{
+			Min:          b.Range[0],
+			Max:          b.Range[1],
+			ExclusiveMax: true,
+		})
+		w := 1.0
+		if b.Weight != nil {
+			w = *b.Weight
+		}
+		ws = append(ws, w)
+	}
+	var err error
+	if h.weights, err = newWeights(ws); err != nil {
+		return &yamlError{
+			line: value.Line,
+			err:  fmt.Errorf("histogram: %w", err),
+		}
+	}
+	return nil
+}
+
+func (h *Histogram) Rand(r *rand.Rand, _, _ float64) float64 {
+	return h.Bins[h.weights.Index(r, len(h.Bins))].Rand(r)
+}
@@ -0,0 +1,155 @@
+package schema
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDistribution_Rand(t *testing.T) {
+	const samples = 10000
+	tests := []struct {
+		name     string
+		data     string
+		min, max float64
+		wantMean float64
+		// delta is an allowed difference of sample mean from wantMean
+		delta float64
+	}{
+		{
+			name:     "normal",
+			data:     "{type: normal, mean: 50, stddev: 10}",
+			max:      100,
+			wantMean: 50,
+			delta:    0.5,
+		},
+		{
+			name:     "lognormal",
+			data:     "{type: lognormal, mu: 0, sigma: 0.5}",
+			max:      100,
+			wantMean: math.Exp(0.125),
+			delta:    0.05,
+		},
+		{
+			name:     "exponential",
+			data:     "{type: exponential, rate: 0.5}",
+			min:      10,
+			max:      1000,
+			wantMean: 12,
+			delta:    0.1,
+		},
+		{
+			name:     "poisson",
+			data:     "{type: poisson, lambda: 4}",
+			max:      100,
+			wantMean: 4,
+			delta:    0.1,
+		},
+		{
+			name:     "poisson approximated by normal",
+			data:     "{type: poisson, lambda: 100}",
+			max:      1000,
+			wantMean: 100,
+			delta:    0.5,
+		},
+		{
+			name:     "histogram",
+			data:     "{type: histogram, bins: [{range: [0, 10], weight: 3}, {range: [90, 100]}]}",
+			max:      100,
+			wantMean: (3*5 + 95) / 4.0,
+			delta:    1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d distribution
+			require.NoError(t, yaml.Unmarshal([]byte(tt.data), &d))
+			r := rand.New(rand.NewSource(1))
+			var sum float64
+			for i := 0; i < samples; i++ {
+				sum += d.Rand(r, tt.min, tt.max)
+			}
+			assert.InDelta(t, tt.wantMean, sum/samples, tt.delta)
+		})
+	}
+}
+
+func TestZipf_Rand(t *testing.T) {
+	// it should be the same as rand.Zipf
+	z := Zipf{S: 2, V: 1}
+	r := rand.New(rand.NewSource(1))
+	want := rand.NewZipf(rand.New(rand.NewSource(1)), 2, 1, 10)
+	for i := 0; i < 1000; i++ {
+		require.Equal(t, float64(want.Uint64()+5), z.Rand(r, 5, 15))
+	}
+}
+
+func TestInteger_Distribution(t *testing.T) {
+	var i Integer
+	require.NoError(t, yaml.Unmarshal([]byte(`
+range: [0, 10]
+distribution: {type: normal, mean: 10, stddev: 5}
+`), &i))
+	r := rand.New(rand.NewSource(1))
+	for j := 0; j < 1000; j++ {
+		var w bytes.Buffer
+		require.NoError(t, i.GenerateJSON(NewContext(), &w, r))
+		num, err := strconv.Atoi(w.String())
+		require.NoError(t, err)
+		require.True(t, num >= 0 && num <= 10, num)
+	}
+}
+
+func TestDistribution_UnmarshalYAML_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name:    "no type",
+			data:    "{mean: 1}",
+			wantErr: "line 1: distribution type is required",
+		},
+		{
+			name:    "unsupported",
+			data:    "{type: cauchy}",
+			wantErr: `line 1: unsupported distribution: "cauchy"`,
+		},
+		{
+			name:    "zero stddev",
+			data:    "{type: normal, mean: 1}",
+			wantErr: "line 1: normal: stddev should be positive",
+		},
+		{
+			name:    "zipf s",
+			data:    "{type: zipf, s: 1}",
+			wantErr: "line 1: zipf: s should be greater than 1",
+		},
+		{
+			name:    "histogram without bins",
+			data:    "{type: histogram}",
+			wantErr: "line 1: histogram: bins are required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d distribution
+			assert.EqualError(t, yaml.Unmarshal([]byte(tt.data), &d), tt.wantErr)
+		})
+	}
+}
+
+func TestInteger_UnmarshalYAML_DistributionWithChoices(t *testing.T) {
+	var i Integer
+	assert.EqualError(t, yaml.Unmarshal([]byte(`
+choices: [1, 2]
+distribution: {type: poisson, lambda: 1}
+`), &i), "line 2: distribution can not be used with choices")
+}
@@ -0,0 +1,1064 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Expr generates the value of an expression computed from values
+// of other fields. Names in expression refer to sibling fields,
+// fields of ancestor objects are referred like in FieldRef: "../../name".
+//
+// Values are JSON values: nil, bool, float64, string,
+// []interface{} and map[string]interface{}.
+type Expr struct {
+	Source string
+	root   exprNode
+	refs   []RefPath
+	// line is a line of expression in schema, it is added to errors
+	line int
+}
+
+// ParseExpr parses an expression
+func ParseExpr(s string) (*Expr, error) {
+	tokens, err := lexExpr(s)
+	if err != nil {
+		return nil, err
+	}
+	p := exprParser{
+		tokens: tokens,
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, t.unexpected()
+	}
+	return &Expr{
+		Source: s,
+		root:   root,
+		refs:   p.refs,
+	}, nil
+}
+
+// unmarshalExpr parses an expression from scalar value
+func unmarshalExpr(value *yaml.Node) (*Expr, error) {
+	var s string
+	if err := value.Decode(&s); err != nil {
+		return nil, err
+	}
+	e, err := ParseExpr(s)
+	if err != nil {
+		return nil, &yamlError{
+			line: value.Line,
+			err:  fmt.Errorf("expr: %w", err),
+		}
+	}
+	e.line = value.Line
+	return e, nil
+}
+
+func (e *Expr) UnmarshalYAML(value *yaml.Node) error {
+	var aux struct {
+		Expr yaml.Node `yaml:"expr"`
+	}
+	if err := value.Decode(&aux); err != nil {
+		return err
+	}
+	if aux.Expr.Kind == 0 {
+		return &yamlError{
+			line: value.Line,
+			err:  errors.New("\"expr\" is required"),
+		}
+	}
+	ex, err := unmarshalExpr(&aux.Expr)
+	if err != nil {
+		return err
+	}
+	*e = *ex
+	return nil
+}
+
+// Eval returns the value of expression
+func (e *Expr) Eval(ctx *Context) (interface{}, error) {
+	v, err := e.root.eval(ctx)
+	if err != nil {
+		if e.line > 0 {
+			err = &yamlError{
+				line: e.line,
+				err:  err,
+			}
+		}
+		return nil, err
+	}
+	return v, nil
+}
+
+func (e *Expr) GenerateJSON(ctx *Context, w io.Writer, _ *rand.Rand) error {
+	v, err := e.Eval(ctx)
+	if err != nil {
+		return err
+	}
+	b, err := appendValue(ctx, nil, v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+func (e *Expr) refPaths() []RefPath {
+	return e.refs
+}
+
+// appendValue appends v encoded as JSON to b
+func appendValue(ctx *Context, b []byte, v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return append(b, nullJSON...), nil
+	case bool:
+		return strconv.AppendBool(b, v), nil
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return nil, fmt.Errorf("%v is not a valid JSON number", v)
+		}
+		return strconv.AppendFloat(b, v, 'f', -1, 64), nil
+	case string:
+		return ctx.stringEncoder.Append(b, []byte(v))
+	case []interface{}:
+		b = append(b, '[')
+		for i, e := range v {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			var err error
+			if b, err = appendValue(ctx, b, e); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, ']'), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b = append(b, '{')
+		for i, k := range keys {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			var err error
+			if b, err = ctx.stringEncoder.Append(b, []byte(k)); err != nil {
+				return nil, err
+			}
+			b = append(b, ':')
+			if b, err = appendValue(ctx, b, v[k]); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, '}'), nil
+	}
+	return nil, fmt.Errorf("unsupported value: %v", v)
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// truthy returns whether v is considered true in conditions
+func truthy(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	}
+	return true
+}
+
+type exprTokenKind int
+
+const (
+	tokEOF exprTokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	// tokRef is a name with "../" prefix
+	tokRef
+	tokOp
+)
+
+type exprToken struct {
+	kind exprTokenKind
+	// text is the source of token, or the value of string
+	text string
+	num  float64
+	pos  int
+}
+
+func (t exprToken) unexpected() error {
+	if t.kind == tokEOF {
+		return errors.New("unexpected end of expression")
+	}
+	return fmt.Errorf("unexpected %q at position %d", t.text, t.pos+1)
+}
+
+// exprOps are operators, longer ones first
+var exprOps = []string{
+	"==", "!=", "<=", ">=", "&&", "||",
+	"+", "-", "*", "/", "%", "<", ">", "!", "(", ")", "[", "]", ",", ".",
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func lexExpr(s string) ([]exprToken, error) {
+	var tokens []exprToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case isDigit(c):
+			for i < len(s) && isDigit(s[i]) {
+				i++
+			}
+			if i+1 < len(s) && s[i] == '.' && isDigit(s[i+1]) {
+				for i++; i < len(s) && isDigit(s[i]); i++ {
+				}
+			}
+			if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+				j := i + 1
+				if j < len(s) && (s[j] == '+' || s[j] == '-') {
+					j++
+				}
+				if j < len(s) && isDigit(s[j]) {
+					for i = j; i < len(s) && isDigit(s[i]); i++ {
+					}
+				}
+			}
+			num, err := strconv.ParseFloat(s[start:i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at position %d", s[start:i], start+1)
+			}
+			tokens = append(tokens, exprToken{kind: tokNumber, text: s[start:i], num: num, pos: start})
+		case c == '"' || c == '\'':
+			str, n, err := lexExprString(s[i:])
+			if err != nil {
+				return nil, fmt.Errorf("%s at position %d", err, start+1)
+			}
+			i += n
+			tokens = append(tokens, exprToken{kind: tokString, text: str, pos: start})
+		case isIdentStart(c):
+			for i < len(s) && (isIdentStart(s[i]) || isDigit(s[i])) {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: tokIdent, text: s[start:i], pos: start})
+		case strings.HasPrefix(s[i:], "../"):
+			for strings.HasPrefix(s[i:], "../") {
+				i += len("../")
+			}
+			if i == len(s) || !isIdentStart(s[i]) {
+				return nil, fmt.Errorf("name expected after %q at position %d", s[start:i], start+1)
+			}
+			for i < len(s) && (isIdentStart(s[i]) || isDigit(s[i])) {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: tokRef, text: s[start:i], pos: start})
+		default:
+			var op string
+			for _, o := range exprOps {
+				if strings.HasPrefix(s[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				r, _ := utf8.DecodeRuneInString(s[i:])
+				return nil, fmt.Errorf("unexpected %q at position %d", r, start+1)
+			}
+			i += len(op)
+			tokens = append(tokens, exprToken{kind: tokOp, text: op, pos: start})
+		}
+	}
+	return append(tokens, exprToken{kind: tokEOF, pos: len(s)}), nil
+}
+
+// lexExprString returns the value of quoted string at the start of s
+// and the length of its source
+func lexExprString(s string) (string, int, error) {
+	quote := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; c {
+		case quote:
+			return b.String(), i + 1, nil
+		case '\\':
+			i++
+			if i == len(s) {
+				break
+			}
+			switch e := s[i]; e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case '\\', '"', '\'':
+				b.WriteByte(e)
+			default:
+				return "", 0, fmt.Errorf("unknown escape sequence \"\\%c\"", e)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, errors.New("unterminated string")
+}
+
+// exprParser is a recursive descent parser of expressions
+type exprParser struct {
+	tokens []exprToken
+	i      int
+	refs   []RefPath
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.i]
+}
+
+func (p *exprParser) next() exprToken {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// acceptOp consumes the next token if it is one of ops
+func (p *exprParser) acceptOp(ops ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokOp {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			p.i++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) expectOp(op string) error {
+	if _, ok := p.acceptOp(op); !ok {
+		return p.peek().unexpected()
+	}
+	return nil
+}
+
+func (p *exprParser) parseBinary(next func() (exprNode, error), ops ...string) (exprNode, error) {
+	x, err := next()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOp(ops...)
+		if !ok {
+			return x, nil
+		}
+		y, err := next()
+		if err != nil {
+			return nil, err
+		}
+		x = &exprBinary{op: op, x: x, y: y}
+	}
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	return p.parseBinary(p.parseAnd, "||")
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	return p.parseBinary(p.parseComparison, "&&")
+}
+
+func (p *exprParser) parseComparison() (exprNode, error) {
+	x, err := p.parseAdd()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.acceptOp("==", "!=", "<=", ">=", "<", ">")
+	if !ok {
+		return x, nil
+	}
+	y, err := p.parseAdd()
+	if err != nil {
+		return nil, err
+	}
+	return &exprBinary{op: op, x: x, y: y}, nil
+}
+
+func (p *exprParser) parseAdd() (exprNode, error) {
+	return p.parseBinary(p.parseMul, "+", "-")
+}
+
+func (p *exprParser) parseMul() (exprNode, error) {
+	return p.parseBinary(p.parseUnary, "*", "/", "%")
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if op, ok := p.acceptOp("-", "!"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &exprUnary{op: op, x: x}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *exprParser) parsePostfix() (exprNode, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOp(".", "[")
+		if !ok {
+			return x, nil
+		}
+		if op == "." {
+			t := p.next()
+			if t.kind != tokIdent {
+				return nil, t.unexpected()
+			}
+			x = &exprMember{x: x, name: t.text}
+			continue
+		}
+		if _, ok := p.acceptOp("]"); ok {
+			x = &exprProjection{x: x}
+			continue
+		}
+		index, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectOp("]"); err != nil {
+			return nil, err
+		}
+		x = &exprIndex{x: x, index: index}
+	}
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return exprLiteral{v: t.num}, nil
+	case tokString:
+		return exprLiteral{v: t.text}, nil
+	case tokRef:
+		path, err := ParseRefPath(t.text)
+		if err != nil {
+			return nil, err
+		}
+		p.refs = append(p.refs, path)
+		return exprRef{path: path}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return exprLiteral{v: true}, nil
+		case "false":
+			return exprLiteral{v: false}, nil
+		case "null":
+			return exprLiteral{v: nil}, nil
+		}
+		if _, ok := p.acceptOp("("); ok {
+			return p.parseCall(t)
+		}
+		path := RefPath{
+			Up:    1,
+			Names: []string{t.text},
+		}
+		p.refs = append(p.refs, path)
+		return exprRef{path: path}, nil
+	case tokOp:
+		if t.text == "(" {
+			x, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectOp(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		}
+	}
+	return nil, t.unexpected()
+}
+
+func (p *exprParser) parseCall(name exprToken) (exprNode, error) {
+	fn, ok := exprFuncs[name.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q at position %d", name.text, name.pos+1)
+	}
+	call := &exprCall{
+		name: name.text,
+		fn:   fn,
+	}
+	if _, ok := p.acceptOp(")"); !ok {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+			if _, ok := p.acceptOp(","); !ok {
+				break
+			}
+		}
+		if err := p.expectOp(")"); err != nil {
+			return nil, err
+		}
+	}
+	if len(call.args) < fn.minArgs || (fn.maxArgs >= 0 && len(call.args) > fn.maxArgs) {
+		return nil, fmt.Errorf("wrong number of arguments to %s at position %d: %d",
+			name.text, name.pos+1, len(call.args))
+	}
+	return call, nil
+}
+
+type exprNode interface {
+	eval(ctx *Context) (interface{}, error)
+}
+
+type exprLiteral struct {
+	v interface{}
+}
+
+func (e exprLiteral) eval(*Context) (interface{}, error) {
+	return e.v, nil
+}
+
+type exprRef struct {
+	path RefPath
+}
+
+func (e exprRef) eval(ctx *Context) (interface{}, error) {
+	raw, err := ctx.fieldValue(e.path)
+	if err != nil || raw == nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// exprMember is a field of object. For arrays, it is a field of each element.
+type exprMember struct {
+	x    exprNode
+	name string
+}
+
+func (e *exprMember) eval(ctx *Context) (interface{}, error) {
+	x, err := e.x.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return member(x, e.name)
+}
+
+func member(x interface{}, name string) (interface{}, error) {
+	switch x := x.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		return x[name], nil
+	case []interface{}:
+		vs := make([]interface{}, 0, len(x))
+		for _, el := range x {
+			v, err := member(el, name)
+			if err != nil {
+				return nil, err
+			}
+			vs = append(vs, v)
+		}
+		return vs, nil
+	}
+	return nil, fmt.Errorf("unable to get %q of %s", name, typeName(x))
+}
+
+// exprProjection is "x[]", which is an array x
+type exprProjection struct {
+	x exprNode
+}
+
+func (e *exprProjection) eval(ctx *Context) (interface{}, error) {
+	x, err := e.x.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch x.(type) {
+	case nil, []interface{}:
+		return x, nil
+	}
+	return nil, fmt.Errorf("%s is not an array", typeName(x))
+}
+
+type exprIndex struct {
+	x, index exprNode
+}
+
+func (e *exprIndex) eval(ctx *Context) (interface{}, error) {
+	x, err := e.x.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	index, err := e.index.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch x := x.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		i, ok := index.(float64)
+		if !ok || i != math.Trunc(i) {
+			return nil, fmt.Errorf("array index should be an integer, got: %s", typeName(index))
+		}
+		if i < 0 || i >= float64(len(x)) {
+			return nil, nil
+		}
+		return x[int(i)], nil
+	case map[string]interface{}:
+		name, ok := index.(string)
+		if !ok {
+			return nil, fmt.Errorf("object index should be a string, got: %s", typeName(index))
+		}
+		return x[name], nil
+	}
+	return nil, fmt.Errorf("unable to index %s", typeName(x))
+}
+
+type exprUnary struct {
+	op string
+	x  exprNode
+}
+
+func (e *exprUnary) eval(ctx *Context) (interface{}, error) {
+	x, err := e.x.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if e.op == "!" {
+		return !truthy(x), nil
+	}
+	return broadcast(x, 0.0, func(x, _ interface{}) (interface{}, error) {
+		switch x := x.(type) {
+		case nil:
+			return nil, nil
+		case float64:
+			return -x, nil
+		}
+		return nil, fmt.Errorf("unable to negate %s", typeName(x))
+	})
+}
+
+type exprBinary struct {
+	op   string
+	x, y exprNode
+}
+
+func (e *exprBinary) eval(ctx *Context) (interface{}, error) {
+	x, err := e.x.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch e.op {
+	case "&&":
+		if !truthy(x) {
+			return false, nil
+		}
+	case "||":
+		if truthy(x) {
+			return true, nil
+		}
+	}
+	y, err := e.y.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch e.op {
+	case "&&", "||":
+		return truthy(y), nil
+	case "==":
+		return reflect.DeepEqual(x, y), nil
+	case "!=":
+		return !reflect.DeepEqual(x, y), nil
+	case "<", "<=", ">", ">=":
+		return compare(e.op, x, y)
+	}
+	return broadcast(x, y, func(x, y interface{}) (interface{}, error) {
+		return arithmetic(e.op, x, y)
+	})
+}
+
+// broadcast applies f to elements of arrays x and y, or to each element
+// of an array and a scalar. Arrays should be of the same length.
+func broadcast(x, y interface{}, f func(x, y interface{}) (interface{}, error)) (interface{}, error) {
+	xs, xArray := x.([]interface{})
+	ys, yArray := y.([]interface{})
+	if !xArray && !yArray {
+		return f(x, y)
+	}
+	if xArray && yArray && len(xs) != len(ys) {
+		return nil, fmt.Errorf("arrays have different lengths: %d and %d", len(xs), len(ys))
+	}
+	n := len(xs)
+	if !xArray {
+		n = len(ys)
+	}
+	vs := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		xi, yi := x, y
+		if xArray {
+			xi = xs[i]
+		}
+		if yArray {
+			yi = ys[i]
+		}
+		v, err := broadcast(xi, yi, f)
+		if err != nil {
+			return nil, err
+		}
+		vs = append(vs, v)
+	}
+	return vs, nil
+}
+
+func arithmetic(op string, x, y interface{}) (interface{}, error) {
+	if x == nil || y == nil {
+		return nil, nil
+	}
+	if xs, ok := x.(string); ok && op == "+" {
+		if ys, ok := y.(string); ok {
+			return xs + ys, nil
+		}
+	}
+	a, aok := x.(float64)
+	b, bok := y.(float64)
+	if !aok || !bok {
+		return nil, fmt.Errorf("unable to apply %q to %s and %s", op, typeName(x), typeName(y))
+	}
+	switch op {
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/", "%":
+		if b == 0 {
+			return nil, errors.New("division by zero")
+		}
+		if op == "%" {
+			return math.Mod(a, b), nil
+		}
+		return a / b, nil
+	}
+	return nil, fmt.Errorf("unknown operator %q", op)
+}
+
+func compare(op string, x, y interface{}) (interface{}, error) {
+	var c int
+	switch x := x.(type) {
+	case float64:
+		y, ok := y.(float64)
+		if !ok {
+			break
+		}
+		switch {
+		case x < y:
+			c = -1
+		case x > y:
+			c = 1
+		}
+		return compareResult(op, c), nil
+	case string:
+		y, ok := y.(string)
+		if !ok {
+			break
+		}
+		return compareResult(op, strings.Compare(x, y)), nil
+	}
+	if x == nil || y == nil {
+		return false, nil
+	}
+	return nil, fmt.Errorf("unable to compare %s and %s", typeName(x), typeName(y))
+}
+
+func compareResult(op string, c int) bool {
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	}
+	return c >= 0
+}
+
+type exprCall struct {
+	name string
+	fn   exprFunc
+	args []exprNode
+}
+
+func (e *exprCall) eval(ctx *Context) (interface{}, error) {
+	args := make([]interface{}, 0, len(e.args))
+	for _, a := range e.args {
+		v, err := a.eval(ctx)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+	v, err := e.fn.call(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", e.name, err)
+	}
+	return v, nil
+}
+
+type exprFunc struct {
+	// minArgs and maxArgs are limits of the number of arguments.
+	// maxArgs is -1 for unlimited number of arguments.
+	minArgs, maxArgs int
+	call             func(args []interface{}) (interface{}, error)
+}
+
+var exprFuncs = map[string]exprFunc{
+	"lower":   stringFunc(strings.ToLower),
+	"upper":   stringFunc(strings.ToUpper),
+	"trim":    stringFunc(strings.TrimSpace),
+	"replace": {3, 3, replaceFunc},
+	"concat":  {1, -1, concatFunc},
+	"len":     {1, 1, lenFunc},
+	"round":   {1, 2, roundFunc},
+	"floor":   numberFunc(math.Floor),
+	"ceil":    numberFunc(math.Ceil),
+	"abs":     numberFunc(math.Abs),
+	"sum":     aggregateFunc(sumOf),
+	"min":     aggregateFunc(minOf),
+	"max":     aggregateFunc(maxOf),
+	"avg":     aggregateFunc(avgOf),
+	"count":   {1, 1, countFunc},
+}
+
+func stringFunc(f func(string) string) exprFunc {
+	return exprFunc{1, 1, func(args []interface{}) (interface{}, error) {
+		switch s := args[0].(type) {
+		case nil:
+			return nil, nil
+		case string:
+			return f(s), nil
+		}
+		return nil, fmt.Errorf("string expected, got: %s", typeName(args[0]))
+	}}
+}
+
+func numberFunc(f func(float64) float64) exprFunc {
+	return exprFunc{1, 1, func(args []interface{}) (interface{}, error) {
+		return broadcast(args[0], nil, func(x, _ interface{}) (interface{}, error) {
+			switch x := x.(type) {
+			case nil:
+				return nil, nil
+			case float64:
+				return f(x), nil
+			}
+			return nil, fmt.Errorf("number expected, got: %s", typeName(x))
+		})
+	}}
+}
+
+func replaceFunc(args []interface{}) (interface{}, error) {
+	var ss [3]string
+	for i, a := range args {
+		s, ok := a.(string)
+		if !ok {
+			return nil, fmt.Errorf("string expected, got: %s", typeName(a))
+		}
+		ss[i] = s
+	}
+	return strings.ReplaceAll(ss[0], ss[1], ss[2]), nil
+}
+
+func concatFunc(args []interface{}) (interface{}, error) {
+	var b strings.Builder
+	for _, a := range args {
+		switch a := a.(type) {
+		case nil:
+		case string:
+			b.WriteString(a)
+		case float64:
+			b.WriteString(strconv.FormatFloat(a, 'f', -1, 64))
+		case bool:
+			b.WriteString(strconv.FormatBool(a))
+		default:
+			return nil, fmt.Errorf("unable to concat %s", typeName(a))
+		}
+	}
+	return b.String(), nil
+}
+
+func lenFunc(args []interface{}) (interface{}, error) {
+	switch a := args[0].(type) {
+	case nil:
+		return 0.0, nil
+	case string:
+		return float64(utf8.RuneCountInString(a)), nil
+	case []interface{}:
+		return float64(len(a)), nil
+	case map[string]interface{}:
+		return float64(len(a)), nil
+	}
+	return nil, fmt.Errorf("unable to get length of %s", typeName(args[0]))
+}
+
+// countFunc returns the number of non-null elements of array
+func countFunc(args []interface{}) (interface{}, error) {
+	switch a := args[0].(type) {
+	case nil:
+		return 0.0, nil
+	case []interface{}:
+		var n float64
+		for _, el := range a {
+			if el != nil {
+				n++
+			}
+		}
+		return n, nil
+	}
+	return nil, fmt.Errorf("array expected, got: %s", typeName(args[0]))
+}
+
+func roundFunc(args []interface{}) (interface{}, error) {
+	var decimals float64
+	if len(args) > 1 {
+		d, ok := args[1].(float64)
+		if !ok {
+			return nil, fmt.Errorf("number of decimals should be a number, got: %s", typeName(args[1]))
+		}
+		decimals = d
+	}
+	scale := math.Pow(10, decimals)
+	return numberFunc(func(x float64) float64 {
+		return math.Round(x*scale) / scale
+	}).call(args[:1])
+}
+
+// aggregateFunc returns a function of either a single array
+// or numbers given as arguments. Nulls are skipped.
+func aggregateFunc(f func(nums []float64) interface{}) exprFunc {
+	return exprFunc{1, -1, func(args []interface{}) (interface{}, error) {
+		if len(args) == 1 {
+			switch a := args[0].(type) {
+			case nil:
+				args = nil
+			case []interface{}:
+				args = a
+			}
+		}
+		nums := make([]float64, 0, len(args))
+		for _, a := range args {
+			switch a := a.(type) {
+			case nil:
+			case float64:
+				nums = append(nums, a)
+			default:
+				return nil, fmt.Errorf("number expected, got: %s", typeName(a))
+			}
+		}
+		return f(nums), nil
+	}}
+}
+
+func sumOf(nums []float64) interface{} {
+	var sum float64
+	for _, n := range nums {
+		sum += n
+	}
+	return sum
+}
+
+func minOf(nums []float64) interface{} {
+	if len(nums) == 0 {
+		return nil
+	}
+	min := nums[0]
+	for _, n := range nums[1:] {
+		min = math.Min(min, n)
+	}
+	return min
+}
+
+func maxOf(nums []float64) interface{} {
+	if len(nums) == 0 {
+		return nil
+	}
+	max := nums[0]
+	for _, n := range nums[1:] {
+		max = math.Max(max, n)
+	}
+	return max
+}
+
+func avgOf(nums []float64) interface{} {
+	if len(nums) == 0 {
+		return nil
+	}
+	return sumOf(nums).(float64) / float64(len(nums))
+}
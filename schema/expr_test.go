@@ -0,0 +1,129 @@
+package schema
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestExpr_Eval(t *testing.T) {
+	ctx := NewContext()
+	ctx.pushScope(scope{
+		"items": []byte(`[{"price":2.5,"qty":2},{"price":1,"qty":3}]`),
+		"age":   []byte(`20`),
+		"title": []byte(`"Hello Big World"`),
+		"empty": []byte(`null`),
+	})
+
+	tests := []struct {
+		expr    string
+		want    interface{}
+		wantErr string
+	}{
+		{expr: "sum(items[].price * items[].qty)", want: 8.0},
+		{expr: "age >= 18", want: true},
+		{expr: `lower(replace(title, " ", "-"))`, want: "hello-big-world"},
+		{expr: "1 + 2 * 3 - -4 / 2", want: 9.0},
+		{expr: "(1 + 2) * 3 % 4", want: 1.0},
+		{expr: "!(age < 18) && title != 'x' || false", want: true},
+		{expr: "max(items[].qty)", want: 3.0},
+		{expr: "min(1, 2, empty)", want: 1.0},
+		{expr: "avg(items[].qty)", want: 2.5},
+		{expr: "count(items)", want: 2.0},
+		{expr: "len(title)", want: 15.0},
+		{expr: "items[1].qty", want: 3.0},
+		{expr: "items[5]", want: nil},
+		{expr: "items[1e19]", want: nil},
+		{expr: "items[-1e19]", want: nil},
+		{expr: "round(10 / 3, 2)", want: 3.33},
+		{expr: `concat(upper("a"), 1, true)`, want: "A1true"},
+		{expr: "empty + 1", want: nil},
+		{expr: "items[].qty * 2", want: []interface{}{4.0, 6.0}},
+		{expr: "../age", want: 20.0},
+		{expr: "../../age", wantErr: "../../age: reference goes beyond root"},
+		{expr: "title * 2", wantErr: `unable to apply "*" to string and number`},
+		{expr: "age / 0", wantErr: "division by zero"},
+		{expr: "lower(age)", wantErr: "lower: string expected, got: number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := ParseExpr(tt.expr)
+			require.NoError(t, err)
+			got, err := e.Eval(ctx)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseExpr_Errors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{expr: "1 +", wantErr: "unexpected end of expression"},
+		{expr: "(1", wantErr: "unexpected end of expression"},
+		{expr: "1 2", wantErr: `unexpected "2" at position 3`},
+		{expr: "a $ b", wantErr: `unexpected '$' at position 3`},
+		{expr: `"abc`, wantErr: "unterminated string at position 1"},
+		{expr: "foo(1)", wantErr: `unknown function "foo" at position 1`},
+		{expr: "replace(a)", wantErr: "wrong number of arguments to replace at position 1: 1"},
+		{expr: "../", wantErr: `name expected after "../" at position 1`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseExpr(tt.expr)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestExpr_UnmarshalYAML(t *testing.T) {
+	var n node
+	err := yaml.Unmarshal([]byte(`
+type: expr
+expr: a +`), &n)
+	assert.EqualError(t, err, "line 3: expr: unexpected end of expression")
+}
+
+func TestObject_GenerateJSON_Expr(t *testing.T) {
+	var o Object
+	require.NoError(t, yaml.Unmarshal([]byte(`
+fields:
+  total:
+    type: expr
+    expr: sum(items[].price * items[].qty)
+  items:
+    type: array
+    length: 2
+    elements:
+      type: object
+      fields:
+        price: {type: int, choices: [3]}
+        qty: {type: int, choices: [2]}
+  title: {type: string, choices: [Hello World]}
+  slug:
+    type: expr
+    expr: lower(replace(title, " ", "-"))
+  bad:
+    type: expr
+    expr: title * 2
+    optional: 1
+`), &o))
+
+	var w bytes.Buffer
+	require.NoError(t, o.GenerateJSON(NewContext(), &w, rand.New(rand.NewSource(1))))
+	assert.Equal(t, `{"total":12,"items":[{"price":3,"qty":2},{"price":3,"qty":2}],"title":"Hello World","slug":"hello-world"}`, w.String())
+
+	o.Fields["bad"].Optional = 0
+	assert.EqualError(t, o.GenerateJSON(NewContext(), &w, rand.New(rand.NewSource(1))),
+		`.bad: line 20: unable to apply "*" to string and number`)
+}
@@ -0,0 +1,238 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// fakeDict is a built-in dictionary of fake data.
+// It can be overridden with a file of the same name added to Context.
+type fakeDict struct {
+	name  string
+	words []string
+}
+
+func (d fakeDict) Rand(ctx *Context, r *rand.Rand) ([]byte, error) {
+	if ctx.hasFile(d.name) {
+		return ctx.Rand(r, d.name)
+	}
+	return []byte(d.words[r.Intn(len(d.words))]), nil
+}
+
+var (
+	firstNameDict   = fakeDict{name: "firstName", words: firstNames}
+	lastNameDict    = fakeDict{name: "lastName", words: lastNames}
+	cityDict        = fakeDict{name: "city", words: cities}
+	streetDict      = fakeDict{name: "street", words: streets}
+	countryCodeDict = fakeDict{name: "countryCode", words: countryCodes}
+	companyDict     = fakeDict{name: "company", words: companies}
+	domainDict      = fakeDict{name: "domain", words: domainWords}
+	userAgentDict   = fakeDict{name: "userAgent", words: userAgents}
+)
+
+type fakeFunc func(ctx *Context, r *rand.Rand) ([]byte, error)
+
+var fakeProviders = map[string]fakeFunc{
+	"firstName":   firstNameDict.Rand,
+	"lastName":    lastNameDict.Rand,
+	"fullName":    fakeFullName,
+	"email":       fakeEmail,
+	"username":    fakeUsername,
+	"street":      fakeStreet,
+	"city":        cityDict.Rand,
+	"countryCode": countryCodeDict.Rand,
+	"phone":       fakePhone,
+	"company":     fakeCompany,
+	"ipv4":        fakeIPv4,
+	"ipv6":        fakeIPv6,
+	"mac":         fakeMAC,
+	"url":         fakeURL,
+	"domain":      fakeDomain,
+	"userAgent":   userAgentDict.Rand,
+	"iban":        fakeIBAN,
+	"creditCard":  fakeCreditCard,
+}
+
+// StringFake generates fake data of given kind, e.g. names or emails
+type StringFake struct {
+	Kind string
+	gen  fakeFunc
+}
+
+func NewStringFake(kind string) (*StringFake, error) {
+	gen, found := fakeProviders[kind]
+	if !found {
+		return nil, fmt.Errorf("unknown fake: %q", kind)
+	}
+	return &StringFake{
+		Kind: kind,
+		gen:  gen,
+	}, nil
+}
+
+func (f *StringFake) Rand(ctx *Context, r *rand.Rand) ([]byte, error) {
+	return f.gen(ctx, r)
+}
+
+func pick(r *rand.Rand, words []string) string {
+	return words[r.Intn(len(words))]
+}
+
+func fakeFullName(ctx *Context, r *rand.Rand) ([]byte, error) {
+	first, err := firstNameDict.Rand(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	last, err := lastNameDict.Rand(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(append(make([]byte, 0, len(first)+len(last)+1), first...), ' '), last...), nil
+}
+
+// fakeUsername returns lowercase username made from names, e.g. "john.smith42"
+func fakeUsername(ctx *Context, r *rand.Rand) ([]byte, error) {
+	first, err := firstNameDict.Rand(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	last, err := lastNameDict.Rand(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, 0, len(first)+len(last)+4)
+	b = append(b, bytes.ToLower(bytes.Replace(first, []byte{' '}, nil, -1))...)
+	b = append(b, pick(r, []string{"", ".", "_"})...)
+	b = append(b, bytes.ToLower(bytes.Replace(last, []byte{' '}, nil, -1))...)
+	if r.Intn(2) == 0 {
+		b = strconv.AppendInt(b, int64(r.Intn(100)), 10)
+	}
+	return b, nil
+}
+
+func fakeDomain(ctx *Context, r *rand.Rand) ([]byte, error) {
+	word, err := domainDict.Rand(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	b := append(bytes.ToLower(word), '.')
+	return append(b, pick(r, topLevelDomains)...), nil
+}
+
+func fakeEmail(ctx *Context, r *rand.Rand) ([]byte, error) {
+	user, err := fakeUsername(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	domain, err := fakeDomain(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(user, '@'), domain...), nil
+}
+
+func fakeURL(ctx *Context, r *rand.Rand) ([]byte, error) {
+	domain, err := fakeDomain(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	b := append([]byte("https://"), domain...)
+	for i := r.Intn(3); i > 0; i-- {
+		b = append(append(b, '/'), pick(r, urlPaths)...)
+	}
+	return b, nil
+}
+
+// fakeStreet returns street address, e.g. "42 Oak St"
+func fakeStreet(ctx *Context, r *rand.Rand) ([]byte, error) {
+	street, err := streetDict.Rand(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	b := strconv.AppendInt(nil, int64(1+r.Intn(9999)), 10)
+	b = append(append(b, ' '), street...)
+	return append(append(b, ' '), pick(r, streetSuffixes)...), nil
+}
+
+func fakeCompany(ctx *Context, r *rand.Rand) ([]byte, error) {
+	company, err := companyDict.Rand(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(company, ' '), pick(r, companySuffixes)...), nil
+}
+
+// fakePhone returns phone number in North American format, e.g. "+1 (212) 555-0123"
+func fakePhone(_ *Context, r *rand.Rand) ([]byte, error) {
+	return []byte(fmt.Sprintf("+1 (%d) %03d-%04d", 200+r.Intn(800), r.Intn(1000), r.Intn(10000))), nil
+}
+
+func fakeIPv4(_ *Context, r *rand.Rand) ([]byte, error) {
+	ip := net.IPv4(byte(1+r.Intn(223)), byte(r.Intn(256)), byte(r.Intn(256)), byte(1+r.Intn(254)))
+	return []byte(ip.String()), nil
+}
+
+func fakeIPv6(_ *Context, r *rand.Rand) ([]byte, error) {
+	ip := make(net.IP, net.IPv6len)
+	randBytes(r, ip)
+	ip[0] = 0x20 | ip[0]&0x1f // global unicast 2000::/3
+	return []byte(ip.String()), nil
+}
+
+func fakeMAC(_ *Context, r *rand.Rand) ([]byte, error) {
+	mac := make(net.HardwareAddr, 6)
+	randBytes(r, mac)
+	mac[0] &^= 1 // unicast
+	return []byte(mac.String()), nil
+}
+
+func randDigits(b []byte, r *rand.Rand, n int) []byte {
+	for i := 0; i < n; i++ {
+		b = append(b, byte('0'+r.Intn(10)))
+	}
+	return b
+}
+
+// fakeIBAN returns IBAN with valid check digits and random numeric BBAN
+func fakeIBAN(_ *Context, r *rand.Rand) ([]byte, error) {
+	f := ibanFormats[r.Intn(len(ibanFormats))]
+	bban := string(randDigits(nil, r, f.bbanLength))
+	// check digits are 98 - (BBAN + country + "00" as number) mod 97,
+	// where letters are replaced with numbers starting from A = 10
+	var num strings.Builder
+	num.WriteString(bban)
+	for _, c := range f.country {
+		num.WriteString(strconv.Itoa(int(c-'A') + 10))
+	}
+	num.WriteString("00")
+	n, _ := new(big.Int).SetString(num.String(), 10)
+	check := 98 - new(big.Int).Mod(n, big.NewInt(97)).Int64()
+	return []byte(fmt.Sprintf("%s%02d%s", f.country, check, bban)), nil
+}
+
+// fakeCreditCard returns card number with valid Luhn check digit
+func fakeCreditCard(_ *Context, r *rand.Rand) ([]byte, error) {
+	f := cardFormats[r.Intn(len(cardFormats))]
+	b := randDigits([]byte(f.prefix), r, f.length-len(f.prefix)-1)
+	return append(b, luhnCheckDigit(b)), nil
+}
+
+// luhnCheckDigit returns digit to be appended to digits to pass Luhn check
+func luhnCheckDigit(digits []byte) byte {
+	var sum int
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-i)%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
@@ -0,0 +1,121 @@
+package schema
+
+// Built-in dictionaries of fake data providers
+
+var firstNames = []string{
+	"James", "Mary", "John", "Patricia", "Robert", "Jennifer", "Michael", "Linda",
+	"William", "Elizabeth", "David", "Barbara", "Richard", "Susan", "Joseph", "Jessica",
+	"Thomas", "Sarah", "Charles", "Karen", "Christopher", "Nancy", "Daniel", "Lisa",
+	"Matthew", "Betty", "Anthony", "Margaret", "Mark", "Sandra", "Donald", "Ashley",
+	"Steven", "Kimberly", "Paul", "Emily", "Andrew", "Donna", "Joshua", "Michelle",
+	"Kenneth", "Dorothy", "Kevin", "Carol", "Brian", "Amanda", "George", "Melissa",
+	"Edward", "Deborah", "Ronald", "Stephanie", "Timothy", "Rebecca", "Jason", "Sharon",
+	"Jeffrey", "Laura", "Ryan", "Cynthia", "Jacob", "Kathleen", "Gary", "Amy",
+}
+
+var lastNames = []string{
+	"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis",
+	"Rodriguez", "Martinez", "Hernandez", "Lopez", "Gonzalez", "Wilson", "Anderson", "Thomas",
+	"Taylor", "Moore", "Jackson", "Martin", "Lee", "Perez", "Thompson", "White",
+	"Harris", "Sanchez", "Clark", "Ramirez", "Lewis", "Robinson", "Walker", "Young",
+	"Allen", "King", "Wright", "Scott", "Torres", "Nguyen", "Hill", "Flores",
+	"Green", "Adams", "Nelson", "Baker", "Hall", "Rivera", "Campbell", "Mitchell",
+	"Carter", "Roberts", "Gomez", "Phillips", "Evans", "Turner", "Diaz", "Parker",
+	"Cruz", "Edwards", "Collins", "Reyes", "Stewart", "Morris", "Morales", "Murphy",
+}
+
+var cities = []string{
+	"New York", "Los Angeles", "Chicago", "Houston", "Phoenix", "Philadelphia", "San Antonio", "San Diego",
+	"Dallas", "San Jose", "Austin", "Jacksonville", "Columbus", "Charlotte", "Indianapolis", "Seattle",
+	"Denver", "Boston", "Portland", "Nashville", "Detroit", "Memphis", "Baltimore", "Milwaukee",
+	"London", "Paris", "Berlin", "Madrid", "Rome", "Amsterdam", "Vienna", "Prague",
+	"Warsaw", "Budapest", "Lisbon", "Dublin", "Stockholm", "Oslo", "Helsinki", "Copenhagen",
+	"Moscow", "Saint Petersburg", "Kyiv", "Istanbul", "Athens", "Zurich", "Brussels", "Munich",
+	"Tokyo", "Seoul", "Beijing", "Shanghai", "Singapore", "Sydney", "Melbourne", "Toronto",
+	"Vancouver", "Montreal", "Mexico City", "Buenos Aires", "Sao Paulo", "Cairo", "Cape Town", "Dubai",
+}
+
+var streets = []string{
+	"Main", "Oak", "Pine", "Maple", "Cedar", "Elm", "Washington", "Lake",
+	"Hill", "Park", "Walnut", "Spring", "North", "Ridge", "Church", "Willow",
+	"Mill", "Sunset", "Railroad", "Jackson", "Cherry", "Highland", "Johnson", "River",
+	"Meadow", "Forest", "Lincoln", "Madison", "Franklin", "Chestnut", "Center", "Valley",
+}
+
+var streetSuffixes = []string{
+	"St", "Ave", "Rd", "Blvd", "Ln", "Dr", "Ct", "Way", "Pl", "Ter",
+}
+
+var countryCodes = []string{
+	"AR", "AT", "AU", "BE", "BG", "BR", "CA", "CH", "CL", "CN", "CO", "CY", "CZ", "DE", "DK", "EE",
+	"EG", "ES", "FI", "FR", "GB", "GR", "HK", "HR", "HU", "ID", "IE", "IL", "IN", "IS", "IT", "JP",
+	"KR", "KZ", "LT", "LU", "LV", "MA", "MX", "MY", "NG", "NL", "NO", "NZ", "PE", "PH", "PL", "PT",
+	"RO", "RS", "RU", "SA", "SE", "SG", "SI", "SK", "TH", "TR", "TW", "UA", "US", "VN", "ZA",
+}
+
+var companies = []string{
+	"Acme", "Globex", "Initech", "Umbrella", "Stark", "Wayne", "Wonka", "Hooli",
+	"Vandelay", "Soylent", "Cyberdyne", "Tyrell", "Aperture", "Gringotts", "Oscorp", "Massive Dynamic",
+	"Pied Piper", "Dunder Mifflin", "Prestige", "Sterling", "Monarch", "Nakatomi", "Virtucon", "Zorg",
+	"Bluth", "Krusty", "Sirius", "Tessier", "Veidt", "Weyland", "Yoyodyne", "Zenith",
+}
+
+var companySuffixes = []string{
+	"Inc", "LLC", "Ltd", "Group", "Corp", "Co", "Holdings", "Industries",
+}
+
+var domainWords = []string{
+	"example", "mail", "post", "inbox", "cloud", "data", "net", "web",
+	"online", "digital", "tech", "soft", "systems", "labs", "works", "hub",
+	"box", "link", "zone", "point", "space", "site", "base", "core",
+}
+
+var topLevelDomains = []string{
+	"com", "net", "org", "io", "dev", "info", "biz", "co",
+}
+
+var urlPaths = []string{
+	"about", "blog", "news", "products", "search", "help", "docs", "contact",
+	"account", "settings", "posts", "users", "items", "catalog", "faq", "events",
+}
+
+var userAgents = []string{
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/80.0.3987.132 Safari/537.36",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:74.0) Gecko/20100101 Firefox/74.0",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_3) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.0.5 Safari/605.1.15",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_3) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/80.0.3987.132 Safari/537.36",
+	"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/80.0.3987.132 Safari/537.36",
+	"Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:74.0) Gecko/20100101 Firefox/74.0",
+	"Mozilla/5.0 (iPhone; CPU iPhone OS 13_3_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.0.5 Mobile/15E148 Safari/604.1",
+	"Mozilla/5.0 (Linux; Android 10; SM-G973F) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/80.0.3987.119 Mobile Safari/537.36",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/80.0.3987.132 Safari/537.36 Edg/80.0.361.66",
+}
+
+// ibanFormats are lengths of numeric BBAN by country
+var ibanFormats = []struct {
+	country    string
+	bbanLength int
+}{
+	{"DE", 18},
+	{"ES", 20},
+	{"FR", 23},
+	{"BE", 12},
+	{"AT", 16},
+	{"PL", 24},
+}
+
+// cardFormats are prefixes and lengths of credit card numbers
+var cardFormats = []struct {
+	prefix string
+	length int
+}{
+	{"4", 16},  // Visa
+	{"51", 16}, // Mastercard
+	{"52", 16},
+	{"53", 16},
+	{"54", 16},
+	{"55", 16},
+	{"34", 15}, // American Express
+	{"37", 15},
+	{"6011", 16}, // Discover
+}
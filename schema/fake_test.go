@@ -0,0 +1,90 @@
+package schema
+
+import (
+	"io/ioutil"
+	"math/big"
+	"math/rand"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringFake_Rand(t *testing.T) {
+	checks := map[string]func(t *testing.T, s string){
+		"email": func(t *testing.T, s string) {
+			assert.Regexp(t, regexp.MustCompile(`^[a-z]+[._]?[a-z]+\d*@[a-z]+\.[a-z]+$`), s)
+		},
+		"ipv4": func(t *testing.T, s string) {
+			assert.NotNil(t, net.ParseIP(s).To4(), s)
+		},
+		"ipv6": func(t *testing.T, s string) {
+			assert.NotNil(t, net.ParseIP(s), s)
+		},
+		"mac": func(t *testing.T, s string) {
+			_, err := net.ParseMAC(s)
+			assert.NoError(t, err)
+		},
+		"iban": func(t *testing.T, s string) {
+			var num strings.Builder
+			for _, c := range s[4:] + s[:4] {
+				if c >= 'A' && c <= 'Z' {
+					num.WriteString(strconv.Itoa(int(c-'A') + 10))
+				} else {
+					num.WriteRune(c)
+				}
+			}
+			n, ok := new(big.Int).SetString(num.String(), 10)
+			require.True(t, ok, s)
+			assert.Equal(t, int64(1), new(big.Int).Mod(n, big.NewInt(97)).Int64(), s)
+		},
+		"creditCard": func(t *testing.T, s string) {
+			assert.Equal(t, s[len(s)-1], luhnCheckDigit([]byte(s[:len(s)-1])), s)
+		},
+	}
+	r := rand.New(rand.NewSource(1))
+	ctx := NewContext()
+	for kind := range fakeProviders {
+		t.Run(kind, func(t *testing.T) {
+			f, err := NewStringFake(kind)
+			require.NoError(t, err)
+			for i := 0; i < 100; i++ {
+				b, err := f.Rand(ctx, r)
+				require.NoError(t, err)
+				require.NotEmpty(t, b)
+				if check, ok := checks[kind]; ok {
+					check(t, string(b))
+				}
+			}
+		})
+	}
+}
+
+func TestLuhnCheckDigit(t *testing.T) {
+	assert.Equal(t, byte('3'), luhnCheckDigit([]byte("7992739871")))
+	assert.Equal(t, byte('1'), luhnCheckDigit([]byte("411111111111111")))
+}
+
+func TestStringFake_Rand_OverrideDict(t *testing.T) {
+	f, err := ioutil.TempFile("", "cities")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString("Gotham\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	ctx := NewContext()
+	defer ctx.Close()
+	require.NoError(t, ctx.AddFile("city", f.Name()))
+
+	fake, err := NewStringFake("city")
+	require.NoError(t, err)
+	b, err := fake.Rand(ctx, rand.New(rand.NewSource(1)))
+	require.NoError(t, err)
+	assert.Equal(t, "Gotham", string(b))
+}
@@ -0,0 +1,317 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RefPath is a path to the value of a field relative to the field
+// which refers to it. Each ".." goes one object up, so "../name" is
+// a sibling field and "../../name" is a field of the parent object.
+// Following names go down into nested objects: "../address/city".
+type RefPath struct {
+	// Up is the number of objects to go up
+	Up    int
+	Names []string
+}
+
+func ParseRefPath(s string) (RefPath, error) {
+	var p RefPath
+	for strings.HasPrefix(s, "../") {
+		p.Up++
+		s = s[len("../"):]
+	}
+	if p.Up == 0 {
+		return RefPath{}, fmt.Errorf("reference should start with \"../\": %q", s)
+	}
+	p.Names = strings.Split(s, "/")
+	for _, name := range p.Names {
+		if name == "" || name == ".." {
+			return RefPath{}, fmt.Errorf("invalid reference: %q", p)
+		}
+	}
+	return p, nil
+}
+
+func (p RefPath) String() string {
+	return strings.Repeat("../", p.Up) + strings.Join(p.Names, "/")
+}
+
+// referrer is implemented by nodes which refer to values of other fields
+type referrer interface {
+	refPaths() []RefPath
+}
+
+// nodeRefs returns references made by n and its children
+// relative to the field which n is a value of
+func nodeRefs(n Node) []RefPath {
+	var refs []RefPath
+	_ = Walk(n, func(n Node) (bool, error) {
+		if o, ok := n.(*Object); ok {
+			// object has already collected references of its fields
+			refs = append(refs, o.outerRefs...)
+			return false, nil
+		}
+		if r, ok := n.(referrer); ok {
+			refs = append(refs, r.refPaths()...)
+		}
+		return true, nil
+	})
+	return refs
+}
+
+// scope holds values of fields of an object being generated,
+// which are referred by other fields
+type scope map[string]json.RawMessage
+
+// FieldRef generates the value of another field,
+// optionally shifted by a random Offset
+type FieldRef struct {
+	Path   RefPath
+	Offset *Offset
+}
+
+func (f *FieldRef) UnmarshalYAML(value *yaml.Node) error {
+	var aux struct {
+		Ref    string  `yaml:"ref"`
+		Offset *Offset `yaml:"offset"`
+	}
+	if err := value.Decode(&aux); err != nil {
+		return err
+	}
+	p, err := ParseRefPath(aux.Ref)
+	if err != nil {
+		return &yamlError{
+			line: value.Line,
+			err:  err,
+		}
+	}
+	*f = FieldRef{
+		Path:   p,
+		Offset: aux.Offset,
+	}
+	return nil
+}
+
+func (f *FieldRef) GenerateJSON(ctx *Context, w io.Writer, r *rand.Rand) error {
+	v, err := ctx.fieldValue(f.Path)
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		_, err := w.Write(nullJSON)
+		return err
+	}
+	if f.Offset != nil {
+		if v, err = f.Offset.apply(v, r); err != nil {
+			return fmt.Errorf("%s: %w", f.Path, err)
+		}
+	}
+	_, err = w.Write(v)
+	return err
+}
+
+// setEpoch sets Epoch of duration Offset to the unit
+// of sibling datetime field which f refers to
+func (f *FieldRef) setEpoch(fields map[string]*Field) {
+	if f.Offset == nil || !f.Offset.Duration || f.Path.Up != 1 || len(f.Path.Names) != 1 {
+		return
+	}
+	if field, found := fields[f.Path.Names[0]]; found {
+		if d, ok := field.Node.(*DateTime); ok {
+			f.Offset.Epoch = d.Format.epoch()
+		}
+	}
+}
+
+func (f *FieldRef) refPaths() []RefPath {
+	return []RefPath{f.Path}
+}
+
+// Offset is a random offset from [Min, Max] added to numbers.
+// If Duration is set, Min and Max are nanoseconds added to times.
+type Offset struct {
+	Min, Max float64
+	Duration bool
+	// Epoch is a unit of times given as numbers since Unix epoch.
+	// Zero means that it is guessed by magnitude of number.
+	Epoch time.Duration
+}
+
+func (o *Offset) UnmarshalYAML(value *yaml.Node) error {
+	var bounds []*yaml.Node
+	switch value.Kind {
+	case yaml.ScalarNode:
+		bounds = []*yaml.Node{value, value}
+	case yaml.SequenceNode:
+		if len(value.Content) != 2 {
+			return &yamlError{
+				line: value.Line,
+				err:  fmt.Errorf("offset range should have 2 elements, got: %d", len(value.Content)),
+			}
+		}
+		bounds = value.Content
+	default:
+		return &yamlError{
+			line: value.Line,
+			err:  errors.New("offset should be either a number, a duration or a range of them"),
+		}
+	}
+	var durations [2]bool
+	var vals [2]float64
+	for i, b := range bounds {
+		switch b.ShortTag() {
+		case "!!int", "!!float":
+			if err := b.Decode(&vals[i]); err != nil {
+				return err
+			}
+		default:
+			d, err := ParseDuration(b.Value)
+			if err != nil {
+				return &yamlError{
+					line: b.Line,
+					err:  err,
+				}
+			}
+			vals[i], durations[i] = float64(d), true
+		}
+	}
+	if durations[0] != durations[1] {
+		return &yamlError{
+			line: value.Line,
+			err:  errors.New("offset bounds should be both numbers or both durations"),
+		}
+	}
+	for _, v := range vals {
+		if !durations[0] && (v < math.MinInt64 || v >= math.MaxInt64) {
+			return &yamlError{
+				line: value.Line,
+				err:  fmt.Errorf("offset %v is out of int64 range", v),
+			}
+		}
+	}
+	if vals[0] > vals[1] {
+		return &yamlError{
+			line: value.Line,
+			err:  errors.New("offset min should not be greater than max"),
+		}
+	}
+	*o = Offset{
+		Min:      vals[0],
+		Max:      vals[1],
+		Duration: durations[0],
+	}
+	return nil
+}
+
+func (o *Offset) integral() bool {
+	return o.Min == math.Trunc(o.Min) && o.Max == math.Trunc(o.Max)
+}
+
+// randInt returns a random integer from [Min, Max]
+func (o *Offset) randInt(r *rand.Rand) int64 {
+	min := int64(o.Min)
+	// overflows give the right result
+	return min + int64(randUint64(r, uint64(int64(o.Max)-min)))
+}
+
+// addInt returns a+b or error if it overflows int64
+func addInt(a, b int64) (int64, error) {
+	sum := a + b
+	if (b > 0 && sum < a) || (b < 0 && sum > a) {
+		return 0, fmt.Errorf("%d%+d overflows int64", a, b)
+	}
+	return sum, nil
+}
+
+// apply adds a random offset to v, which is either a number or
+// a time if offset is a duration
+func (o *Offset) apply(v json.RawMessage, r *rand.Rand) (json.RawMessage, error) {
+	if o.Duration {
+		return o.applyDuration(v, r)
+	}
+	var num json.Number
+	if err := json.Unmarshal(v, &num); err != nil {
+		return nil, fmt.Errorf("unable to add number to %s", v)
+	}
+	if i, err := num.Int64(); err == nil && o.integral() {
+		if i, err = addInt(i, o.randInt(r)); err != nil {
+			return nil, err
+		}
+		return strconv.AppendInt(nil, i, 10), nil
+	}
+	f, err := num.Float64()
+	if err != nil {
+		return nil, err
+	}
+	f += o.Min + r.Float64()*(o.Max-o.Min)
+	return strconv.AppendFloat(nil, f, 'f', -1, 64), nil
+}
+
+// millisEpochThreshold is the minimum magnitude of numeric times
+// which are guessed to be in milliseconds. In seconds, it is year 5138.
+const millisEpochThreshold = 1e11
+
+// applyDuration adds a random duration to v, which is a time either
+// in RFC 3339 or date format or a number since Unix epoch.
+// The result has the same format and precision as v.
+func (o *Offset) applyDuration(v json.RawMessage, r *rand.Rand) (json.RawMessage, error) {
+	d := time.Duration(o.randInt(r))
+	var s string
+	if err := json.Unmarshal(v, &s); err != nil {
+		epoch, err := strconv.ParseInt(string(v), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unable to add duration to %s: time should be RFC 3339, date or integer since Unix epoch", v)
+		}
+		unit := o.Epoch
+		if unit == 0 {
+			unit = time.Second
+			if epoch >= millisEpochThreshold || epoch <= -millisEpochThreshold {
+				unit = time.Millisecond
+			}
+		}
+		if epoch, err = addInt(epoch, int64(d/unit)); err != nil {
+			return nil, err
+		}
+		return strconv.AppendInt(nil, epoch, 10), nil
+	}
+	layout, ok := timeLayout(s)
+	if !ok {
+		return nil, fmt.Errorf("unable to add duration to %s: time should be RFC 3339, date or integer since Unix epoch", v)
+	}
+	t, _ := time.Parse(layout, s)
+	return json.Marshal(t.Add(d).Format(layout))
+}
+
+// timeLayout returns layout of s, which is either
+// RFC 3339 time or date, with the same precision as s
+func timeLayout(s string) (string, bool) {
+	if _, err := time.Parse(dateLayout, s); err == nil {
+		return dateLayout, true
+	}
+	if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+		return "", false
+	}
+	// fraction of seconds follows "2006-01-02T15:04:05"
+	const fracStart = len("2006-01-02T15:04:05")
+	if len(s) <= fracStart || s[fracStart] != '.' {
+		return time.RFC3339, true
+	}
+	digits := 0
+	for _, c := range s[fracStart+1:] {
+		if c < '0' || c > '9' {
+			break
+		}
+		digits++
+	}
+	return "2006-01-02T15:04:05." + strings.Repeat("0", digits) + "Z07:00", true
+}
@@ -0,0 +1,269 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseRefPath(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    RefPath
+		wantErr bool
+	}{
+		{
+			s:    "../a",
+			want: RefPath{Up: 1, Names: []string{"a"}},
+		},
+		{
+			s:    "../../a/b",
+			want: RefPath{Up: 2, Names: []string{"a", "b"}},
+		},
+		{
+			s:       "a",
+			wantErr: true,
+		},
+		{
+			s:       "../a/../b",
+			wantErr: true,
+		},
+		{
+			s:       "../",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseRefPath(tt.s)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.s, got.String())
+		})
+	}
+}
+
+func TestObject_GenerateJSON_FieldRefs(t *testing.T) {
+	var o Object
+	require.NoError(t, yaml.Unmarshal([]byte(`
+fields:
+  end:
+    ref: ../start
+    offset: [1d, 30d]
+  start:
+    type: datetime
+    range: [2020-01-01T00:00:00Z, 2020-12-31T00:00:00Z]
+  fullName:
+    type: string
+    template: "{{.first}} {{.last}}"
+  first: {type: string, choices: [John]}
+  last: {type: string, choices: [Doe]}
+  count: {type: int, choices: [10]}
+  nested:
+    type: object
+    fields:
+      more:
+        ref: ../../count
+        offset: 5
+`), &o))
+
+	for seed := int64(0); seed < 10; seed++ {
+		var w bytes.Buffer
+		require.NoError(t, o.GenerateJSON(NewContext(), &w, rand.New(rand.NewSource(seed))))
+		var v struct {
+			Start, End time.Time
+			FullName   string
+			Nested     struct {
+				More int
+			}
+		}
+		require.NoError(t, json.Unmarshal(w.Bytes(), &v), w.String())
+		assert.True(t, v.End.Sub(v.Start) >= day && v.End.Sub(v.Start) <= 30*day, w.String())
+		assert.Equal(t, "John Doe", v.FullName)
+		assert.Equal(t, 15, v.Nested.More)
+	}
+}
+
+func TestObject_GenerateJSON_FieldRefs_Order(t *testing.T) {
+	var o Object
+	require.NoError(t, yaml.Unmarshal([]byte(`
+fields:
+  b: {ref: ../a}
+  a: {type: int, choices: [1]}
+  c: {ref: ../d, optional: 1}
+  d: {type: int, choices: [2], optional: 1}
+  e: {ref: ../d}
+`), &o))
+	var w bytes.Buffer
+	require.NoError(t, o.GenerateJSON(NewContext(), &w, rand.New(rand.NewSource(1))))
+	require.Equal(t, `{"b":1,"a":1,"e":null}`, w.String())
+}
+
+func TestSchema_Validate_FieldRefs(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name: "cycle",
+			data: `
+root:
+  type: object
+  fields:
+    nested:
+      type: object
+      fields:
+        a: {ref: ../b}
+        b: {ref: ../c}
+        c: {ref: ../a}
+`,
+			wantErr: ".nested: fields refer to each other: a -> b -> c -> a",
+		},
+		{
+			name: "unknown field",
+			data: `
+root:
+  type: object
+  fields:
+    a: {ref: ../b}
+`,
+			wantErr: `.a: reference to unknown field: "b"`,
+		},
+		{
+			name: "beyond root",
+			data: `
+root:
+  type: object
+  fields:
+    a: {ref: ../../b}
+`,
+			wantErr: "../b: reference goes beyond root",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s Schema
+			require.NoError(t, yaml.Unmarshal([]byte(tt.data), &s))
+			assert.EqualError(t, s.Validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestObject_GenerateJSON_FieldRefs_Epoch(t *testing.T) {
+	for _, format := range []string{"unix", "unixmilli"} {
+		t.Run(format, func(t *testing.T) {
+			var o Object
+			require.NoError(t, yaml.Unmarshal([]byte(`
+fields:
+  start:
+    type: datetime
+    range: [2020-01-01T00:00:00Z, 2020-01-01T00:00:00Z]
+    format: `+format+`
+  end:
+    ref: ../start
+    offset: 1d
+`), &o))
+			var w bytes.Buffer
+			require.NoError(t, o.GenerateJSON(NewContext(), &w, rand.New(rand.NewSource(1))))
+			unit := int64(1)
+			if format == "unixmilli" {
+				unit = 1000
+			}
+			start := int64(1577836800) * unit
+			assert.Equal(t, fmt.Sprintf(`{"start":%d,"end":%d}`, start, start+86400*unit), w.String())
+		})
+	}
+}
+
+func TestOffset_apply(t *testing.T) {
+	tests := []struct {
+		name    string
+		offset  string
+		v       string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "rfc3339",
+			offset: "1h",
+			v:      `"2020-01-01T00:00:00+03:00"`,
+			want:   `"2020-01-01T01:00:00+03:00"`,
+		},
+		{
+			name:   "precision",
+			offset: "1ns",
+			v:      `"2020-01-01T00:00:00.100Z"`,
+			want:   `"2020-01-01T00:00:00.100Z"`,
+		},
+		{
+			name:   "date",
+			offset: "2d",
+			v:      `"2020-01-01"`,
+			want:   `"2020-01-03"`,
+		},
+		{
+			name:   "seconds since epoch",
+			offset: "1m",
+			v:      "1577836800",
+			want:   "1577836860",
+		},
+		{
+			name:   "milliseconds since epoch",
+			offset: "1s",
+			v:      "1577836800000",
+			want:   "1577836801000",
+		},
+		{
+			name:    "not a time",
+			offset:  "1s",
+			v:       `"yesterday"`,
+			wantErr: "unable to add duration",
+		},
+		{
+			name:    "overflow",
+			offset:  "1",
+			v:       "9223372036854775807",
+			wantErr: "overflows int64",
+		},
+		{
+			name:   "full int64 range",
+			offset: "[-9223372036854775808, 9223372036854775000]",
+			v:      "0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var o Offset
+			require.NoError(t, yaml.Unmarshal([]byte(tt.offset), &o))
+			got, err := o.apply(json.RawMessage(tt.v), rand.New(rand.NewSource(1)))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.want != "" {
+				assert.Equal(t, tt.want, string(got))
+			}
+		})
+	}
+}
+
+func TestOffset_UnmarshalYAML_OutOfRange(t *testing.T) {
+	var o Offset
+	err := yaml.Unmarshal([]byte("[-1e19, 1e19]"), &o)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "out of int64 range")
+}
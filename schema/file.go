@@ -18,6 +18,12 @@ type LineSource interface {
 	WriteLine([]byte) error
 
 	Rand(r *rand.Rand) ([]byte, error)
+
+	// Line returns i-th line
+	Line(i int) ([]byte, error)
+
+	// Len returns the number of lines
+	Len() int
 }
 
 type LineSourceFlusher interface {
@@ -80,6 +86,14 @@ func (f *bufferedSource) Rand(r *rand.Rand) ([]byte, error) {
 	return f.lines[r.Intn(len(f.lines))], nil
 }
 
+func (f *bufferedSource) Line(i int) ([]byte, error) {
+	return f.lines[i], nil
+}
+
+func (f *bufferedSource) Len() int {
+	return len(f.lines)
+}
+
 var ErrBufferFull = errors.New("buffer is full")
 
 func (f *bufferedSource) WriteLine(line []byte) error {
@@ -119,6 +133,10 @@ func (f *index) WriteLine(line []byte) error {
 	return nil
 }
 
+func (f index) Len() int {
+	return len(f)
+}
+
 type indexedReaderAt struct {
 	index
 	io.ReaderAt
@@ -132,12 +150,15 @@ func newIndexedReaderAt(r io.ReaderAt, size uint64) *indexedReaderAt {
 }
 
 func (f *indexedReaderAt) Rand(r *rand.Rand) ([]byte, error) {
-	toInd := r.Intn(len(f.index))
+	return f.Line(r.Intn(len(f.index)))
+}
+
+func (f *indexedReaderAt) Line(i int) ([]byte, error) {
 	var from int64
-	if toInd > 0 {
-		from = f.index[toInd-1]
+	if i > 0 {
+		from = f.index[i-1]
 	}
-	buff := make([]byte, f.index[toInd]-from-1)
+	buff := make([]byte, f.index[i]-from-1)
 	_, err := f.ReadAt(buff, from)
 	return buff, err
 }
@@ -4,79 +4,314 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"math/rand"
 	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
 var defaultFloatRange = FloatRange{
-	Min: 0,
-	Max: 1,
+	Min:          0,
+	Max:          1,
+	ExclusiveMax: true,
 }
 
+// NumberFormat is a format of numbers in JSON
+type NumberFormat string
+
+const (
+	// PlainNumberFormat is a number in decimal notation: 12.3
+	PlainNumberFormat NumberFormat = "number"
+	// StringNumberFormat is a string with a number, for floats
+	// it has a fixed number of decimals: "12.30"
+	StringNumberFormat NumberFormat = "string"
+	// ExponentNumberFormat is a number in exponent notation: 1.23e+01
+	ExponentNumberFormat NumberFormat = "exponent"
+)
+
 type Float struct {
-	Range   *FloatRange `yaml:"range"`
-	Choices []float64   `yaml:"choices"`
+	Range   *FloatRange
+	Choices []float64
+	// weights of choices, nil means that they are equally likely
+	weights *weights
+	// Distribution of numbers in Range, nil means uniform
+	Distribution Distribution
+	// Step is a step which numbers are rounded to multiples of, 0 means no rounding
+	Step float64
+	// Decimals is the number of digits after decimal point in Step
+	Decimals int
+	Format   NumberFormat
 }
 
 func (f *Float) UnmarshalYAML(value *yaml.Node) error {
-	type raw Float
-	var aux raw
+	var aux struct {
+		Range        *FloatRange      `yaml:"range"`
+		Choices      *weightedChoices `yaml:"choices"`
+		Weights      []float64        `yaml:"weights"`
+		Distribution *distribution    `yaml:"distribution"`
+		Precision    *int             `yaml:"precision"`
+		Step         *float64         `yaml:"step"`
+		Format       NumberFormat     `yaml:"format"`
+	}
 	if err := value.Decode(&aux); err != nil {
 		return err
 	}
-	*f = Float(aux)
+	*f = Float{
+		Range:  aux.Range,
+		Format: aux.Format,
+	}
+	switch f.Format {
+	case "":
+		f.Format = PlainNumberFormat
+	case PlainNumberFormat, StringNumberFormat, ExponentNumberFormat:
+	default:
+		return &yamlError{
+			line: value.Line,
+			err:  fmt.Errorf("unsupported format: %q", f.Format),
+		}
+	}
+	if aux.Choices != nil {
+		f.Choices = make([]float64, len(aux.Choices.values))
+		var err error
+		f.weights, err = unmarshalChoices(value, aux.Choices, aux.Weights, func(j int, v *yaml.Node) error {
+			return v.Decode(&f.Choices[j])
+		})
+		if err != nil {
+			return err
+		}
+	} else if aux.Weights != nil {
+		return &yamlError{
+			line: value.Line,
+			err:  errors.New("weights are given without choices"),
+		}
+	}
 	if f.Range != nil && len(f.Choices) > 0 {
 		return &yamlError{
 			line: value.Line,
 			err:  errors.New("float should have either range or choices, not both"),
 		}
 	}
+	if aux.Distribution != nil {
+		if len(f.Choices) > 0 {
+			return &yamlError{
+				line: value.Line,
+				err:  errors.New("distribution can not be used with choices"),
+			}
+		}
+		f.Distribution = aux.Distribution.Distribution
+	}
 	if f.Range == nil && len(f.Choices) == 0 {
 		f.Range = &defaultFloatRange
 	}
+	if err := f.setStep(aux.Precision, aux.Step); err != nil {
+		return &yamlError{
+			line: value.Line,
+			err:  err,
+		}
+	}
 	return nil
 }
 
+// maxPrecision is the maximum number of decimals, which are
+// significant for float64 numbers
+const maxPrecision = 17
+
+// setStep sets Step either to 10^-precision or to step
+func (f *Float) setStep(precision *int, step *float64) error {
+	switch {
+	case precision != nil && step != nil:
+		return errors.New("float should have either precision or step, not both")
+	case precision != nil:
+		if *precision < 0 || *precision > maxPrecision {
+			return fmt.Errorf("precision should be between 0 and %d", maxPrecision)
+		}
+		f.Step, f.Decimals = math.Pow10(-*precision), *precision
+	case step != nil:
+		if *step <= 0 {
+			return errors.New("step should be positive")
+		}
+		f.Step = *step
+		s := strconv.FormatFloat(f.Step, 'f', -1, 64)
+		if i := strings.IndexByte(s, '.'); i >= 0 {
+			f.Decimals = len(s) - i - 1
+		}
+		if f.Decimals > maxPrecision {
+			return fmt.Errorf("step should not have more than %d decimals", maxPrecision)
+		}
+	default:
+		return nil
+	}
+	if f.Range != nil {
+		if min, max := f.Range.steps(f.Step); min > max {
+			return fmt.Errorf("there are no multiples of %v in range", f.Step)
+		}
+	}
+	return nil
+}
+
+// round rounds x to the nearest multiple of Step in Range
+func (f *Float) round(x float64) float64 {
+	k := math.Round(x / f.Step)
+	if f.Range != nil {
+		min, max := f.Range.steps(f.Step)
+		k = math.Max(min, math.Min(max, k))
+	}
+	// get rid of floating point errors, like 3*0.1 = 0.30000000000000004
+	scale := math.Pow10(f.Decimals)
+	return math.Round(k*f.Step*scale) / scale
+}
+
+// appendFloat appends x in Format to b
+func (f *Float) appendFloat(b []byte, x float64) []byte {
+	switch f.Format {
+	case StringNumberFormat:
+		decimals := -1
+		if f.Step != 0 {
+			decimals = f.Decimals
+		}
+		b = append(b, '"')
+		b = strconv.AppendFloat(b, x, 'f', decimals, 64)
+		return append(b, '"')
+	case ExponentNumberFormat:
+		return strconv.AppendFloat(b, x, 'e', -1, 64)
+	}
+	return strconv.AppendFloat(b, x, 'f', -1, 64)
+}
+
 func (f *Float) GenerateJSON(_ *Context, w io.Writer, r *rand.Rand) error {
 	var num float64
-	if f.Range != nil {
+	if f.Range != nil && f.Distribution != nil {
+		num = f.Range.clamp(f.Distribution.Rand(r, f.Range.Min, f.Range.Max))
+	} else if f.Range != nil {
 		num = f.Range.Rand(r)
 	} else if l := len(f.Choices); l > 0 {
-		num = f.Choices[r.Intn(l)]
+		num = f.Choices[f.weights.Index(r, l)]
 	}
-	_, err := w.Write([]byte(strconv.FormatFloat(num, 'f', -1, 64)))
+	if f.Step != 0 {
+		num = f.round(num)
+	}
+	_, err := w.Write(f.appendFloat(nil, num))
 	return err
 }
 
+// FloatRange is a range of floats between Min and Max.
+// Each bound is included, unless it is exclusive.
 type FloatRange struct {
-	Min, Max float64
+	Min, Max                   float64
+	ExclusiveMin, ExclusiveMax bool
 }
 
 func (r FloatRange) Rand(rnd *rand.Rand) float64 {
 	if r.Min == r.Max {
 		return r.Min
 	}
-	return r.Min + (r.Max-r.Min)*rnd.Float64()
+	for {
+		var f float64
+		if r.ExclusiveMax {
+			f = rnd.Float64()
+		} else {
+			// [0, 1] with 1 included
+			f = float64(rnd.Int63n(1<<53+1)) / (1 << 53)
+		}
+		x := r.Min + (r.Max-r.Min)*f
+		if math.IsInf(r.Max-r.Min, 0) {
+			x = r.Min*(1-f) + r.Max*f
+		}
+		// floating point errors can get x out of range
+		if r.contains(x) {
+			return x
+		}
+	}
 }
 
-func (r *FloatRange) UnmarshalYAML(value *yaml.Node) (err error) {
-	*r = defaultFloatRange
-	switch value.Kind {
-	case yaml.ScalarNode:
-		if err := r.unmarshalYAMLScalar(value); err != nil {
-			return err
-		}
-	case yaml.SequenceNode:
-		if err := r.unmarshalYAMLSequence(value); err != nil {
-			return err
+func (r FloatRange) contains(x float64) bool {
+	return (x > r.Min || x == r.Min && !r.ExclusiveMin) &&
+		(x < r.Max || x == r.Max && !r.ExclusiveMax)
+}
+
+// lowest returns the minimum float in range
+func (r FloatRange) lowest() float64 {
+	if r.ExclusiveMin {
+		return math.Nextafter(r.Min, math.Inf(1))
+	}
+	return r.Min
+}
+
+// highest returns the maximum float in range
+func (r FloatRange) highest() float64 {
+	if r.ExclusiveMax {
+		return math.Nextafter(r.Max, math.Inf(-1))
+	}
+	return r.Max
+}
+
+// steps returns the minimum and maximum k, such that k*step is in range
+func (r FloatRange) steps(step float64) (float64, float64) {
+	min, integral := quotient(r.Min, step)
+	if !integral {
+		min = math.Ceil(min)
+	} else if r.ExclusiveMin {
+		min++
+	}
+	max, integral := quotient(r.Max, step)
+	if !integral {
+		max = math.Floor(max)
+	} else if r.ExclusiveMax {
+		max--
+	}
+	return min, max
+}
+
+// quotient returns x/y and whether it is integral ignoring floating
+// point errors, so quotient(0.3, 0.1) is 3, not 2.9999999999999996
+func quotient(x, y float64) (float64, bool) {
+	q := x / y
+	if r := math.Round(q); math.Abs(q-r) < 1e-9 {
+		return r, true
+	}
+	return q, false
+}
+
+// clamp returns x limited to range
+func (r FloatRange) clamp(x float64) float64 {
+	return math.Max(r.lowest(), math.Min(r.highest(), x))
+}
+
+func (r *FloatRange) UnmarshalYAML(value *yaml.Node) error {
+	b, err := unmarshalBounds(value, true)
+	if err != nil {
+		return err
+	}
+	*r = FloatRange{
+		Min:          defaultFloatRange.Min,
+		Max:          defaultFloatRange.Max,
+		ExclusiveMin: b.exclusiveMin,
+		ExclusiveMax: b.exclusiveMax,
+	}
+	for _, bound := range []struct {
+		s string
+		f *float64
+	}{
+		{s: b.min, f: &r.Min},
+		{s: b.max, f: &r.Max},
+	} {
+		if bound.s == "" {
+			continue
 		}
-	default:
-		return &yamlError{
-			line: value.Line,
-			err:  fmt.Errorf("length should be {float64 | [float64, float64]}, got: %s", value.Tag),
+		f, err := strconv.ParseFloat(bound.s, 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return &yamlError{
+				line: value.Line,
+				err:  fmt.Errorf("invalid float: %q", bound.s),
+			}
 		}
+		*bound.f = f
+	}
+	if b.defaultExclusiveMax && r.Min == r.Max {
+		// the only value of range is included
+		r.ExclusiveMax = false
 	}
 	if err := r.validate(); err != nil {
 		return &yamlError{
@@ -88,21 +323,46 @@ func (r *FloatRange) UnmarshalYAML(value *yaml.Node) (err error) {
 }
 
 func (r *FloatRange) validate() error {
-	if r.Min >= r.Max {
-		return errors.New("min should be less than max")
+	if r.Min > r.Max || r.lowest() > r.highest() {
+		return errors.New("range is empty")
 	}
 	return nil
 }
 
-func (r *FloatRange) unmarshalYAMLScalar(value *yaml.Node) error {
-	return value.Decode(&r.Max)
+func (f *Float) Count(*Context) (uint64, bool) {
+	if f.Range != nil {
+		if f.Step == 0 {
+			return 0, false
+		}
+		min, max := f.Range.steps(f.Step)
+		// conversion of floats out of uint64 is implementation-defined
+		if max-min >= 1<<64 {
+			return 0, false
+		}
+		return uint64(max-min) + 1, true
+	}
+	return uint64(len(f.enumerateChoices())), true
+}
+
+func (f *Float) Enumerate(*Context) ([][]byte, bool, error) {
+	if f.Range != nil {
+		return nil, false, nil
+	}
+	return f.enumerateChoices(), true, nil
 }
 
-func (r *FloatRange) unmarshalYAMLSequence(value *yaml.Node) error {
-	var aux [2]float64
-	if err := value.Decode(&aux); err != nil {
-		return err
+// enumerateChoices returns distinct reachable choices as they are generated
+func (f *Float) enumerateChoices() [][]byte {
+	vs := make([][]byte, 0, len(f.Choices))
+	seen := make(map[string]struct{}, len(f.Choices))
+	for i, c := range f.Choices {
+		if !f.weights.reachable(i) {
+			continue
+		}
+		if f.Step != 0 {
+			c = f.round(c)
+		}
+		vs = appendDistinct(vs, seen, f.appendFloat(nil, c))
 	}
-	r.Min, r.Max = aux[0], aux[1]
-	return nil
+	return vs
 }
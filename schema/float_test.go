@@ -1,6 +1,11 @@
 package schema
 
 import (
+	"bytes"
+	"math"
+	"math/rand"
+	"regexp"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -24,18 +29,76 @@ func TestFloatRange_UnmarshalYAML(t *testing.T) {
 			name: "max only",
 			data: "3.14",
 			wantRes: FloatRange{
-				Min: defaultFloatRange.Min,
-				Max: 3.14,
+				Min:          defaultFloatRange.Min,
+				Max:          3.14,
+				ExclusiveMax: true,
 			},
 		},
 		{
 			name: "min max",
 			data: "[1, 3.14]",
+			wantRes: FloatRange{
+				Min:          1,
+				Max:          3.14,
+				ExclusiveMax: true,
+			},
+		},
+		{
+			name: "interval",
+			data: `"(1, 3.14]"`,
+			wantRes: FloatRange{
+				Min:          1,
+				Max:          3.14,
+				ExclusiveMin: true,
+			},
+		},
+		{
+			name: "mapping",
+			data: "{min: -1, max: 1}",
+			wantRes: FloatRange{
+				Min: -1,
+				Max: 1,
+			},
+		},
+		{
+			name: "single value",
+			data: `"[1, 1]"`,
 			wantRes: FloatRange{
 				Min: 1,
-				Max: 3.14,
+				Max: 1,
 			},
 		},
+		{
+			name: "single value in array",
+			data: "[1, 1]",
+			wantRes: FloatRange{
+				Min: 1,
+				Max: 1,
+			},
+		},
+		{
+			name: "single value in max",
+			data: "0",
+			wantRes: FloatRange{
+				Min: 0,
+				Max: 0,
+			},
+		},
+		{
+			name:    "empty",
+			data:    `"[1, 1)"`,
+			wantErr: true,
+		},
+		{
+			name:    "min > max",
+			data:    "[1, -1]",
+			wantErr: true,
+		},
+		{
+			name:    "unclosed interval",
+			data:    `"[1, 2"`,
+			wantErr: true,
+		},
 		{
 			name:    "empty array",
 			data:    "[]",
@@ -88,7 +151,7 @@ func TestFloatRange_validate(t *testing.T) {
 				Min: 0,
 				Max: 0,
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name: "min > max",
@@ -110,3 +173,154 @@ func TestFloatRange_validate(t *testing.T) {
 		})
 	}
 }
+
+func TestFloatRange_Rand(t *testing.T) {
+	tests := []FloatRange{
+		{Min: 1, Max: 1},
+		{Min: 0, Max: 1, ExclusiveMin: true, ExclusiveMax: true},
+		{Min: -math.MaxFloat64, Max: math.MaxFloat64},
+		{Min: 1, Max: math.Nextafter(1, 2), ExclusiveMin: true},
+	}
+	for _, r := range tests {
+		rnd := rand.New(rand.NewSource(1))
+		for i := 0; i < 1000; i++ {
+			x := r.Rand(rnd)
+			require.True(t, r.contains(x), "%+v: %v", r, x)
+		}
+	}
+}
+
+func TestFloat_Count(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		want      uint64
+		wantCount bool
+	}{
+		{name: "choices", data: "{choices: [1, 2, 2]}", want: 2, wantCount: true},
+		{name: "no step", data: "{range: [0, 1]}"},
+		{name: "step", data: "{range: [0, 1], step: 0.25}", want: 4, wantCount: true},
+		{name: "too many steps", data: "{range: [-1e300, 1e300], step: 1}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f Float
+			require.NoError(t, yaml.Unmarshal([]byte(tt.data), &f))
+			got, ok := f.Count(NewContext())
+			require.Equal(t, tt.wantCount, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFloat_GenerateJSON_Rounding(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		check func(t *testing.T, s string)
+	}{
+		{
+			name: "precision",
+			data: "{range: [1, 100], precision: 2}",
+			check: func(t *testing.T, s string) {
+				assert.Regexp(t, `^\d+(\.\d{1,2})?$`, s)
+			},
+		},
+		{
+			name: "step",
+			data: "{range: [0, 1], step: 0.05}",
+			check: func(t *testing.T, s string) {
+				f, err := strconv.ParseFloat(s, 64)
+				require.NoError(t, err)
+				assert.InDelta(t, 0, math.Remainder(f, 0.05), 1e-9, s)
+				assert.True(t, f >= 0 && f < 1, s)
+				assert.Regexp(t, `^0(\.\d{1,2})?$`, s)
+			},
+		},
+		{
+			name: "rounding within range",
+			data: "{range: [0.11, 0.3], precision: 1}",
+			check: func(t *testing.T, s string) {
+				assert.Equal(t, "0.2", s)
+			},
+		},
+		{
+			name: "string",
+			data: "{range: [1, 2], precision: 2, format: string}",
+			check: func(t *testing.T, s string) {
+				assert.Regexp(t, `^"1\.\d\d"$`, s)
+			},
+		},
+		{
+			name: "exponent",
+			data: "{choices: [1234.5], format: exponent}",
+			check: func(t *testing.T, s string) {
+				assert.Equal(t, "1.2345e+03", s)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f Float
+			require.NoError(t, yaml.Unmarshal([]byte(tt.data), &f))
+			r := rand.New(rand.NewSource(1))
+			for i := 0; i < 100; i++ {
+				var w bytes.Buffer
+				require.NoError(t, f.GenerateJSON(NewContext(), &w, r))
+				tt.check(t, w.String())
+			}
+		})
+	}
+}
+
+func TestFloat_UnmarshalYAML_Rounding(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr *regexp.Regexp
+	}{
+		{
+			name:    "precision and step",
+			data:    "{precision: 1, step: 0.5}",
+			wantErr: regexp.MustCompile("either precision or step"),
+		},
+		{
+			name:    "no multiples in range",
+			data:    "{range: [0.11, 0.12], precision: 1}",
+			wantErr: regexp.MustCompile("no multiples of 0.1 in range"),
+		},
+		{
+			name:    "too large precision",
+			data:    "{precision: 400}",
+			wantErr: regexp.MustCompile("precision should be between 0 and 17"),
+		},
+		{
+			name:    "negative precision",
+			data:    "{precision: -1}",
+			wantErr: regexp.MustCompile("precision should be between 0 and 17"),
+		},
+		{
+			name:    "too small step",
+			data:    "{step: 1e-300}",
+			wantErr: regexp.MustCompile("step should not have more than 17 decimals"),
+		},
+		{
+			name:    "negative step",
+			data:    "{step: -1}",
+			wantErr: regexp.MustCompile("step should be positive"),
+		},
+		{
+			name:    "unsupported format",
+			data:    "{format: hex}",
+			wantErr: regexp.MustCompile(`unsupported format: "hex"`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f Float
+			err := yaml.Unmarshal([]byte(tt.data), &f)
+			require.Error(t, err)
+			assert.Regexp(t, tt.wantErr, err.Error())
+		})
+	}
+}
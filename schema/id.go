@@ -0,0 +1,207 @@
+package schema
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"time"
+)
+
+// IDKind is a kind of identifiers
+type IDKind string
+
+const (
+	UUIDv4 IDKind = "uuid4"
+	// UUIDv7 is time-ordered UUID
+	UUIDv7 IDKind = "uuid7"
+	ULID   IDKind = "ulid"
+	KSUID  IDKind = "ksuid"
+	NanoID IDKind = "nanoid"
+)
+
+const defaultNanoIDAlphabet = "_-0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+var defaultNanoIDLength = Length{
+	Min: 21,
+	Max: 21,
+}
+
+// StringID generates identifiers. Time-based identifiers use Context.Now(),
+// so all of them are generated with the same time during the run.
+type StringID struct {
+	Kind IDKind
+}
+
+// NewStringID returns StringRander for identifiers of given kind.
+// alphabet and length are used only for NanoID, zero values mean defaults.
+func NewStringID(kind IDKind, alphabet string, length *Length) (StringRander, error) {
+	switch kind {
+	case UUIDv4, UUIDv7, ULID, KSUID:
+		return StringID{Kind: kind}, nil
+	case NanoID:
+		if alphabet == "" {
+			alphabet = defaultNanoIDAlphabet
+		}
+		if length == nil {
+			length = &defaultNanoIDLength
+		}
+		return NewStringChars(alphabet, *length), nil
+	}
+	return nil, fmt.Errorf("unknown id: %q", kind)
+}
+
+func (s StringID) Rand(ctx *Context, r *rand.Rand) ([]byte, error) {
+	switch s.Kind {
+	case UUIDv4:
+		return uuid4(r), nil
+	case UUIDv7:
+		return uuid7(&ctx.ids, ctx.Now(), r), nil
+	case ULID:
+		return ulid(&ctx.ids, ctx.Now(), r), nil
+	case KSUID:
+		return ksuid(ctx.Now(), r), nil
+	}
+	return nil, fmt.Errorf("unknown id: %q", s.Kind)
+}
+
+func randBytes(r *rand.Rand, b []byte) {
+	for i := 0; i < len(b); i += 8 {
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], r.Uint64())
+		copy(b[i:], buf[:])
+	}
+}
+
+func uuid4(r *rand.Rand) []byte {
+	var u [16]byte
+	randBytes(r, u[:])
+	return formatUUID(u, 4)
+}
+
+// idState is a state of time-ordered identifiers kept in Context.
+// Like clocks of UUIDv7 and ULID generators, it keeps identifiers
+// generated in the same millisecond increasing.
+type idState struct {
+	uuid7Milli uint64
+	// uuid7Counter is 12-bit counter of UUIDv7 within millisecond
+	uuid7Counter uint16
+	ulidMilli    uint64
+	// ulidRand is random part of the last ULID
+	ulidRand [10]byte
+}
+
+// maxUUID7Counter is the maximum of 12-bit counter of UUIDv7
+const maxUUID7Counter = 1<<12 - 1
+
+// uuid7 returns UUIDv7 with counter in rand_a field,
+// which is method 1 of RFC 9562 monotonicity
+func uuid7(s *idState, t time.Time, r *rand.Rand) []byte {
+	ms := uint64(unixMilli(t))
+	if ms <= s.uuid7Milli && s.uuid7Counter < maxUUID7Counter {
+		ms = s.uuid7Milli
+		s.uuid7Counter++
+	} else {
+		if ms <= s.uuid7Milli {
+			// counter overflows, so time goes forward
+			ms = s.uuid7Milli + 1
+		}
+		// counter starts from random value with the most
+		// significant bit unset to leave room for increments
+		s.uuid7Counter = uint16(r.Intn(1 << 11))
+	}
+	s.uuid7Milli = ms
+	var u [16]byte
+	putUint48(u[:6], ms)
+	binary.BigEndian.PutUint16(u[6:8], s.uuid7Counter)
+	randBytes(r, u[8:])
+	return formatUUID(u, 7)
+}
+
+// putUint48 puts 48-bit big-endian n to b
+func putUint48(b []byte, n uint64) {
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(n)
+		n >>= 8
+	}
+}
+
+func formatUUID(u [16]byte, version byte) []byte {
+	u[6] = u[6]&0x0f | version<<4
+	u[8] = u[8]&0x3f | 0x80 // RFC 4122 variant
+	b := make([]byte, 36)
+	hex.Encode(b[0:8], u[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u[10:])
+	return b
+}
+
+const crockfordBase32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulid returns ULID. ULIDs generated in the same millisecond
+// have their random part incremented as in monotonic ULID spec.
+func ulid(s *idState, t time.Time, r *rand.Rand) []byte {
+	ms := uint64(unixMilli(t))
+	if ms <= s.ulidMilli && increment(s.ulidRand[:]) {
+		ms = s.ulidMilli
+	} else {
+		if ms <= s.ulidMilli {
+			// random part overflows, so time goes forward
+			ms = s.ulidMilli + 1
+		}
+		randBytes(r, s.ulidRand[:])
+	}
+	s.ulidMilli = ms
+	var u [16]byte
+	putUint48(u[:6], ms)
+	copy(u[6:], s.ulidRand[:])
+	// 128 bits are encoded in 26 characters of 5 bits,
+	// so the first character has only 3 bits
+	n := new(big.Int).SetBytes(u[:])
+	b := make([]byte, 26)
+	mask := big.NewInt(31)
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i] = crockfordBase32[new(big.Int).And(n, mask).Int64()]
+		n.Rsh(n, 5)
+	}
+	return b
+}
+
+// increment increments big-endian number b.
+// It returns false if it overflows.
+func increment(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	// ksuidEpoch is the start of KSUID time in Unix seconds
+	ksuidEpoch = 1400000000
+)
+
+func ksuid(t time.Time, r *rand.Rand) []byte {
+	var k [20]byte
+	binary.BigEndian.PutUint32(k[:4], uint32(t.Unix()-ksuidEpoch))
+	randBytes(r, k[4:])
+	n := new(big.Int).SetBytes(k[:])
+	b := make([]byte, 27)
+	base, mod := big.NewInt(62), new(big.Int)
+	for i := len(b) - 1; i >= 0; i-- {
+		n.DivMod(n, base, mod)
+		b[i] = base62[mod.Int64()]
+	}
+	return b
+}
@@ -0,0 +1,97 @@
+package schema
+
+import (
+	"math/rand"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestString_UnmarshalYAML_ID(t *testing.T) {
+	now := time.Date(2020, 3, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		data string
+		want *regexp.Regexp
+	}{
+		{
+			data: "id: uuid4",
+			want: regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`),
+		},
+		{
+			// 2020-03-15T12:00:00Z is 0x170de10ae00 ms
+			data: "id: uuid7",
+			want: regexp.MustCompile(`^0170de10-ae00-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`),
+		},
+		{
+			data: "id: ulid",
+			want: regexp.MustCompile(`^01E3F11BG0[0-9A-HJKMNP-TV-Z]{16}$`),
+		},
+		{
+			data: "id: ksuid",
+			want: regexp.MustCompile(`^[0-9A-Za-z]{27}$`),
+		},
+		{
+			data: "id: nanoid",
+			want: regexp.MustCompile(`^[0-9A-Za-z_-]{21}$`),
+		},
+		{
+			data: "{id: nanoid, alphabet: abc, length: 5}",
+			want: regexp.MustCompile(`^[abc]{5}$`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			var s String
+			require.NoError(t, yaml.Unmarshal([]byte(tt.data), &s))
+			ctx1, ctx2 := NewContext(), NewContext()
+			ctx1.SetNow(now)
+			ctx2.SetNow(now)
+			r1, r2 := rand.New(rand.NewSource(1)), rand.New(rand.NewSource(1))
+			for i := 0; i < 10; i++ {
+				id, err := s.Rand(ctx1, r1)
+				require.NoError(t, err)
+				require.Regexp(t, tt.want, string(id))
+				same, err := s.Rand(ctx2, r2)
+				require.NoError(t, err)
+				require.Equal(t, id, same)
+			}
+		})
+	}
+}
+
+func TestKSUID_time(t *testing.T) {
+	// KSUID with zero payload at the start of its epoch is all zeros
+	b := ksuid(time.Unix(ksuidEpoch, 0), rand.New(fakeSource(0)))
+	require.Equal(t, "000000000000000000000000000", string(b))
+}
+
+func TestStringID_Rand_Ordered(t *testing.T) {
+	for _, kind := range []IDKind{UUIDv7, ULID} {
+		t.Run(string(kind), func(t *testing.T) {
+			ctx := NewContext()
+			ctx.SetNow(time.Date(2020, 3, 15, 12, 0, 0, 0, time.UTC))
+			r := rand.New(rand.NewSource(1))
+			s := StringID{Kind: kind}
+			var prev string
+			// more than values of UUIDv7 counter
+			for i := 0; i < 5000; i++ {
+				id, err := s.Rand(ctx, r)
+				require.NoError(t, err)
+				require.Greater(t, string(id), prev)
+				prev = string(id)
+			}
+		})
+	}
+}
+
+func TestIncrement(t *testing.T) {
+	b := []byte{0x01, 0xff}
+	require.True(t, increment(b))
+	require.Equal(t, []byte{0x02, 0x00}, b)
+	b = []byte{0xff, 0xff}
+	require.False(t, increment(b))
+	require.Equal(t, []byte{0x00, 0x00}, b)
+}
@@ -4,35 +4,82 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
+	"math/big"
 	"math/rand"
 	"strconv"
 
 	"gopkg.in/yaml.v3"
 )
 
-var defaultIntRange = IntRange{
-	Min: 0,
-	Max: 100,
-}
+var defaultIntRange = *NewIntRange(0, 100)
 
 type Integer struct {
-	Range   *IntRange `yaml:"range"`
-	Choices []int64   `yaml:"choices"`
+	Range   *IntRange
+	Choices []int64
+	// weights of choices, nil means that they are equally likely
+	weights *weights
+	// Distribution of numbers in Range, nil means uniform
+	Distribution Distribution
+	// Format is either PlainNumberFormat or StringNumberFormat
+	Format NumberFormat
 }
 
 func (i *Integer) UnmarshalYAML(value *yaml.Node) error {
-	type raw Integer
-	var aux raw
+	var aux struct {
+		Range        *IntRange        `yaml:"range"`
+		Choices      *weightedChoices `yaml:"choices"`
+		Weights      []float64        `yaml:"weights"`
+		Distribution *distribution    `yaml:"distribution"`
+		Format       NumberFormat     `yaml:"format"`
+	}
 	if err := value.Decode(&aux); err != nil {
 		return err
 	}
-	*i = Integer(aux)
+	*i = Integer{
+		Range:  aux.Range,
+		Format: aux.Format,
+	}
+	switch i.Format {
+	case "":
+		i.Format = PlainNumberFormat
+	case PlainNumberFormat, StringNumberFormat:
+	default:
+		return &yamlError{
+			line: value.Line,
+			err:  fmt.Errorf("unsupported format: %q", i.Format),
+		}
+	}
+	if aux.Choices != nil {
+		i.Choices = make([]int64, len(aux.Choices.values))
+		var err error
+		i.weights, err = unmarshalChoices(value, aux.Choices, aux.Weights, func(j int, v *yaml.Node) error {
+			return v.Decode(&i.Choices[j])
+		})
+		if err != nil {
+			return err
+		}
+	} else if aux.Weights != nil {
+		return &yamlError{
+			line: value.Line,
+			err:  errors.New("weights are given without choices"),
+		}
+	}
 	if i.Range != nil && len(i.Choices) > 0 {
 		return &yamlError{
 			line: value.Line,
 			err:  errors.New("integer should have either range or choices, not both"),
 		}
 	}
+	if aux.Distribution != nil {
+		if len(i.Choices) > 0 {
+			return &yamlError{
+				line: value.Line,
+				err:  errors.New("distribution can not be used with choices"),
+			}
+		}
+		i.Distribution = aux.Distribution.Distribution
+	}
 	if i.Range == nil && len(i.Choices) == 0 {
 		i.Range = &defaultIntRange
 	}
@@ -40,41 +87,204 @@ func (i *Integer) UnmarshalYAML(value *yaml.Node) error {
 }
 
 func (i *Integer) GenerateJSON(_ *Context, w io.Writer, r *rand.Rand) error {
-	var num int64
-	if i.Range != nil {
-		num = i.Range.Rand(r)
+	var b []byte
+	if i.Format == StringNumberFormat {
+		b = append(b, '"')
+	}
+	if i.Range != nil && i.Distribution != nil {
+		min, max := i.Range.floats()
+		b = i.Range.appendClamped(b, i.Distribution.Rand(r, min, max))
+	} else if i.Range != nil {
+		b = i.Range.appendRand(b, r)
 	} else if l := len(i.Choices); l > 0 {
-		num = i.Choices[r.Intn(l)]
+		b = strconv.AppendInt(b, i.Choices[i.weights.Index(r, l)], 10)
+	}
+	if i.Format == StringNumberFormat {
+		b = append(b, '"')
 	}
-	_, err := w.Write([]byte(strconv.FormatInt(num, 10)))
+	_, err := w.Write(b)
 	return err
 }
 
+// IntRange is a range of integers between Min and Max, both included.
+// Bounds can be arbitrarily large integers, nil bound is 0.
 type IntRange struct {
-	Min, Max int64
+	Min, Max *big.Int
+	// kind tells whether bounds fit in int64 or uint64.
+	// It is computed on use if range is not made by NewIntRange or UnmarshalYAML.
+	kind intRangeKind
+	// min is bits of Min as int64 or uint64
+	min uint64
+	// size is Max-Min if bounds fit in int64 or uint64
+	size uint64
 }
 
-func (r IntRange) Rand(rnd *rand.Rand) int64 {
-	return r.Min + rnd.Int63n(r.Max-r.Min+1)
+type intRangeKind int
+
+const (
+	unclassifiedIntRange intRangeKind = iota
+	bigIntRange
+	int64Range
+	uint64Range
+)
+
+// NewIntRange returns a range [min, max]
+func NewIntRange(min, max int64) *IntRange {
+	return newBigIntRange(big.NewInt(min), big.NewInt(max))
 }
 
-func (r *IntRange) UnmarshalYAML(value *yaml.Node) error {
-	*r = defaultIntRange
-	switch value.Kind {
-	case yaml.ScalarNode:
-		if err := r.unmarshalYAMLScalar(value); err != nil {
-			return err
+func newBigIntRange(min, max *big.Int) *IntRange {
+	if min == nil {
+		min = new(big.Int)
+	}
+	if max == nil {
+		max = new(big.Int)
+	}
+	r := &IntRange{
+		Min:  min,
+		Max:  max,
+		kind: bigIntRange,
+	}
+	switch {
+	case min.IsInt64() && max.IsInt64():
+		r.kind = int64Range
+		r.min = uint64(min.Int64())
+		// overflow gives the right result
+		r.size = uint64(max.Int64()) - r.min
+	case min.IsUint64() && max.IsUint64():
+		r.kind = uint64Range
+		r.min = min.Uint64()
+		r.size = max.Uint64() - r.min
+	}
+	return r
+}
+
+// classified returns r with kind computed
+func (r IntRange) classified() IntRange {
+	if r.kind != unclassifiedIntRange {
+		return r
+	}
+	return *newBigIntRange(r.Min, r.Max)
+}
+
+// Rand returns a random integer from range
+func (r IntRange) Rand(rnd *rand.Rand) *big.Int {
+	r = r.classified()
+	switch r.kind {
+	case int64Range:
+		return big.NewInt(int64(r.min + randUint64(rnd, r.size)))
+	case uint64Range:
+		return new(big.Int).SetUint64(r.min + randUint64(rnd, r.size))
+	}
+	limit := new(big.Int).Sub(r.Max, r.Min)
+	limit.Add(limit, big.NewInt(1))
+	n := new(big.Int).Rand(rnd, limit)
+	return n.Add(n, r.Min)
+}
+
+// appendRand appends a random integer from range to b
+func (r IntRange) appendRand(b []byte, rnd *rand.Rand) []byte {
+	switch r = r.classified(); r.kind {
+	case int64Range:
+		return strconv.AppendInt(b, int64(r.min+randUint64(rnd, r.size)), 10)
+	case uint64Range:
+		return strconv.AppendUint(b, r.min+randUint64(rnd, r.size), 10)
+	}
+	return r.Rand(rnd).Append(b, 10)
+}
+
+// appendNth appends k-th integer of range to b.
+// It should be used only for ranges of int64 or uint64.
+func (r IntRange) appendNth(b []byte, k uint64) []byte {
+	if r = r.classified(); r.kind == int64Range {
+		return strconv.AppendInt(b, int64(r.min+k), 10)
+	}
+	return strconv.AppendUint(b, r.min+k, 10)
+}
+
+// randUint64 returns a random integer from [0, n]
+func randUint64(r *rand.Rand, n uint64) uint64 {
+	switch {
+	case n < math.MaxInt64:
+		return uint64(r.Int63n(int64(n) + 1))
+	case n == math.MaxUint64:
+		return r.Uint64()
+	}
+	// n >= 2^63-1, so at least half of values are accepted
+	for {
+		if v := r.Uint64(); v <= n {
+			return v
 		}
-	case yaml.SequenceNode:
-		if err := r.unmarshalYAMLSequence(value); err != nil {
-			return err
+	}
+}
+
+// floats returns bounds as floats
+func (r IntRange) floats() (float64, float64) {
+	r = r.classified()
+	min, _ := new(big.Float).SetInt(r.Min).Float64()
+	max, _ := new(big.Float).SetInt(r.Max).Float64()
+	return min, max
+}
+
+// appendClamped appends x rounded to the nearest integer in range to b
+func (r IntRange) appendClamped(b []byte, x float64) []byte {
+	r = r.classified()
+	x = math.Round(x)
+	if min, max := r.floats(); x <= min {
+		return r.Min.Append(b, 10)
+	} else if x >= max {
+		return r.Max.Append(b, 10)
+	}
+	n, _ := big.NewFloat(x).Int(nil)
+	// x is rounded, but not exactly between min and max as floats
+	if n.Cmp(r.Min) < 0 {
+		n = r.Min
+	} else if n.Cmp(r.Max) > 0 {
+		n = r.Max
+	}
+	return n.Append(b, 10)
+}
+
+// count returns the number of integers in range
+func (r IntRange) count() (uint64, bool) {
+	r = r.classified()
+	if r.kind == bigIntRange || r.size == math.MaxUint64 {
+		return 0, false
+	}
+	return r.size + 1, true
+}
+
+func (r *IntRange) UnmarshalYAML(value *yaml.Node) error {
+	b, err := unmarshalBounds(value, false)
+	if err != nil {
+		return err
+	}
+	min, max := defaultIntRange.Min, defaultIntRange.Max
+	for _, bound := range []struct {
+		s         string
+		n         **big.Int
+		exclusive bool
+		// delta is added to exclusive bound
+		delta int64
+	}{
+		{s: b.min, n: &min, exclusive: b.exclusiveMin, delta: 1},
+		{s: b.max, n: &max, exclusive: b.exclusiveMax, delta: -1},
+	} {
+		if bound.s != "" {
+			n, ok := new(big.Int).SetString(bound.s, 0)
+			if !ok {
+				return &yamlError{
+					line: value.Line,
+					err:  fmt.Errorf("invalid integer: %q", bound.s),
+				}
+			}
+			*bound.n = n
 		}
-	default:
-		return &yamlError{
-			line: value.Line,
-			err:  fmt.Errorf("length should be {int64 | [int64, int64}, got: %s", value.Tag),
+		if bound.exclusive {
+			*bound.n = new(big.Int).Add(*bound.n, big.NewInt(bound.delta))
 		}
 	}
+	*r = *newBigIntRange(min, max)
 	if err := r.validate(); err != nil {
 		return &yamlError{
 			line: value.Line,
@@ -85,21 +295,50 @@ func (r *IntRange) UnmarshalYAML(value *yaml.Node) error {
 }
 
 func (r *IntRange) validate() error {
-	if r.Min >= r.Max {
-		return errors.New("min should be less than max")
+	if c := r.classified(); c.Min.Cmp(c.Max) > 0 {
+		return errors.New("range is empty")
 	}
 	return nil
 }
 
-func (r *IntRange) unmarshalYAMLScalar(value *yaml.Node) error {
-	return value.Decode(&r.Max)
+func (i *Integer) Count(*Context) (uint64, bool) {
+	if i.Range != nil {
+		return i.Range.count()
+	}
+	return uint64(len(i.enumerateChoices())), true
 }
 
-func (r *IntRange) unmarshalYAMLSequence(value *yaml.Node) error {
-	var aux [2]int64
-	if err := value.Decode(&aux); err != nil {
-		return err
+func (i *Integer) Enumerate(*Context) ([][]byte, bool, error) {
+	if i.Range == nil {
+		return i.enumerateChoices(), true, nil
 	}
-	r.Min, r.Max = aux[0], aux[1]
-	return nil
+	cnt, ok := i.Range.count()
+	if !ok || cnt > maxEnumerated {
+		return nil, false, nil
+	}
+	vs := make([][]byte, 0, cnt)
+	for k := uint64(0); k < cnt; k++ {
+		vs = append(vs, i.format(i.Range.appendNth(nil, k)))
+	}
+	return vs, true, nil
+}
+
+// format returns number n in Format
+func (i *Integer) format(n []byte) []byte {
+	if i.Format != StringNumberFormat {
+		return n
+	}
+	return append(append([]byte{'"'}, n...), '"')
+}
+
+// enumerateChoices returns distinct reachable choices
+func (i *Integer) enumerateChoices() [][]byte {
+	vs := make([][]byte, 0, len(i.Choices))
+	seen := make(map[string]struct{}, len(i.Choices))
+	for j, c := range i.Choices {
+		if i.weights.reachable(j) {
+			vs = appendDistinct(vs, seen, i.format(strconv.AppendInt(nil, c, 10)))
+		}
+	}
+	return vs
 }
@@ -1,6 +1,9 @@
 package schema
 
 import (
+	"bytes"
+	"math/big"
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -12,29 +15,72 @@ func TestIntRange_UnmarshalYAML(t *testing.T) {
 	tests := []struct {
 		name    string
 		data    string
-		wantRes IntRange
+		wantMin string
+		wantMax string
 		wantErr bool
 	}{
 		{
 			name:    "default",
 			data:    "",
-			wantRes: defaultIntRange,
+			wantMin: "0",
+			wantMax: "100",
 		},
 		{
-			name: "max only",
-			data: "11",
-			wantRes: IntRange{
-				Min: defaultIntRange.Min,
-				Max: 11,
-			},
+			name:    "max only",
+			data:    "11",
+			wantMin: "0",
+			wantMax: "11",
 		},
 		{
-			name: "min max",
-			data: "[1, 11]",
-			wantRes: IntRange{
-				Min: 1,
-				Max: 11,
-			},
+			name:    "min max",
+			data:    "[1, 11]",
+			wantMin: "1",
+			wantMax: "11",
+		},
+		{
+			name:    "single value",
+			data:    "[5, 5]",
+			wantMin: "5",
+			wantMax: "5",
+		},
+		{
+			name:    "interval",
+			data:    `"(1, 11)"`,
+			wantMin: "2",
+			wantMax: "10",
+		},
+		{
+			name:    "mapping",
+			data:    "{min: -5, max: 5, exclusiveMax: true}",
+			wantMin: "-5",
+			wantMax: "4",
+		},
+		{
+			name:    "full int64",
+			data:    "[-9223372036854775808, 9223372036854775807]",
+			wantMin: "-9223372036854775808",
+			wantMax: "9223372036854775807",
+		},
+		{
+			name:    "big",
+			data:    "[0, 100000000000000000000000]",
+			wantMin: "0",
+			wantMax: "100000000000000000000000",
+		},
+		{
+			name:    "empty interval",
+			data:    `"[1, 1)"`,
+			wantErr: true,
+		},
+		{
+			name:    "min > max",
+			data:    "[1, -1]",
+			wantErr: true,
+		},
+		{
+			name:    "not an integer",
+			data:    "[1, 2.5]",
+			wantErr: true,
 		},
 		{
 			name:    "empty array",
@@ -61,7 +107,8 @@ func TestIntRange_UnmarshalYAML(t *testing.T) {
 				return
 			}
 			require.NoError(t, err)
-			require.Equal(t, tt.wantRes, r)
+			assert.Equal(t, tt.wantMin, r.Min.String())
+			assert.Equal(t, tt.wantMax, r.Max.String())
 		})
 	}
 }
@@ -77,24 +124,24 @@ func TestIntRange_validate(t *testing.T) {
 		{
 			name: "min < max",
 			r: IntRange{
-				Min: -1,
-				Max: 1,
+				Min: big.NewInt(-1),
+				Max: big.NewInt(1),
 			},
 			wantErr: false,
 		},
 		{
 			name: "min = max",
 			r: IntRange{
-				Min: 0,
-				Max: 0,
+				Min: big.NewInt(0),
+				Max: big.NewInt(0),
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name: "min > max",
 			r: IntRange{
-				Min: 1,
-				Max: -1,
+				Min: big.NewInt(1),
+				Max: big.NewInt(-1),
 			},
 			wantErr: true,
 		},
@@ -110,3 +157,130 @@ func TestIntRange_validate(t *testing.T) {
 		})
 	}
 }
+
+func TestIntRange_Rand(t *testing.T) {
+	tests := []struct {
+		name string
+		r    IntRange
+	}{
+		{name: "zero", r: IntRange{}},
+		{name: "literal", r: IntRange{Min: big.NewInt(-3), Max: big.NewInt(3)}},
+		{name: "constructed", r: *NewIntRange(-3, 3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			min, max := tt.r.Min, tt.r.Max
+			if min == nil {
+				min, max = new(big.Int), new(big.Int)
+			}
+			rnd := rand.New(rand.NewSource(1))
+			for i := 0; i < 1000; i++ {
+				n := tt.r.Rand(rnd)
+				require.True(t, n.Cmp(min) >= 0 && n.Cmp(max) <= 0, n)
+			}
+			cnt, ok := tt.r.count()
+			require.True(t, ok)
+			assert.Equal(t, new(big.Int).Sub(max, min).Uint64()+1, cnt)
+		})
+	}
+}
+
+func TestIntRange_appendRand(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max string
+	}{
+		{name: "single value", min: "7", max: "7"},
+		{name: "small", min: "-3", max: "3"},
+		{name: "full int64", min: "-9223372036854775808", max: "9223372036854775807"},
+		{name: "full uint64", min: "0", max: "18446744073709551615"},
+		{name: "above uint64", min: "18446744073709551615", max: "18446744073709551625"},
+		{name: "big", min: "-100000000000000000000000", max: "100000000000000000000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r IntRange
+			require.NoError(t, yaml.Unmarshal([]byte("["+tt.min+", "+tt.max+"]"), &r))
+			rnd := rand.New(rand.NewSource(1))
+			for i := 0; i < 1000; i++ {
+				n, ok := new(big.Int).SetString(string(r.appendRand(nil, rnd)), 10)
+				require.True(t, ok)
+				require.True(t, n.Cmp(r.Min) >= 0 && n.Cmp(r.Max) <= 0, n)
+			}
+		})
+	}
+}
+
+func TestInteger_GenerateJSON_StringFormat(t *testing.T) {
+	var i Integer
+	require.NoError(t, yaml.Unmarshal([]byte(`{range: [18446744073709551615, 18446744073709551615], format: string}`), &i))
+	var w bytes.Buffer
+	require.NoError(t, i.GenerateJSON(NewContext(), &w, rand.New(rand.NewSource(1))))
+	assert.Equal(t, `"18446744073709551615"`, w.String())
+}
+
+func TestInteger_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantChoices []int64
+		wantWeights bool
+		wantErr     bool
+	}{
+		{
+			name:        "plain choices",
+			data:        "choices: [1, 2, 3]",
+			wantChoices: []int64{1, 2, 3},
+		},
+		{
+			name:        "inline weights",
+			data:        "choices: [{value: 1, weight: 9}, 2]",
+			wantChoices: []int64{1, 2},
+			wantWeights: true,
+		},
+		{
+			name:        "separate weights",
+			data:        "{choices: [1, 2], weights: [9, 1]}",
+			wantChoices: []int64{1, 2},
+			wantWeights: true,
+		},
+		{
+			name:        "equal weights",
+			data:        "{choices: [1, 2], weights: [3, 3]}",
+			wantChoices: []int64{1, 2},
+		},
+		{
+			name:    "both weights",
+			data:    "{choices: [{value: 1, weight: 9}, 2], weights: [9, 1]}",
+			wantErr: true,
+		},
+		{
+			name:    "number of weights mismatch",
+			data:    "{choices: [1, 2], weights: [1]}",
+			wantErr: true,
+		},
+		{
+			name:    "weights without choices",
+			data:    "weights: [1]",
+			wantErr: true,
+		},
+		{
+			name:    "no value",
+			data:    "choices: [{weight: 1}]",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var i Integer
+			err := yaml.Unmarshal([]byte(tt.data), &i)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantChoices, i.Choices)
+			require.Equal(t, tt.wantWeights, i.weights != nil)
+		})
+	}
+}
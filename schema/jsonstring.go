@@ -0,0 +1,112 @@
+package schema
+
+import (
+	"errors"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// InvalidUTF8 defines how StringEncoder handles invalid UTF-8
+type InvalidUTF8 int
+
+const (
+	// ReplaceInvalidUTF8 replaces each invalid byte with U+FFFD
+	ReplaceInvalidUTF8 InvalidUTF8 = iota
+	// FailOnInvalidUTF8 makes StringEncoder return ErrInvalidUTF8
+	FailOnInvalidUTF8
+)
+
+var ErrInvalidUTF8 = errors.New("invalid UTF-8")
+
+// StringEncoder encodes strings to JSON according to RFC 8259.
+// Zero value escapes only characters required to be escaped by RFC.
+type StringEncoder struct {
+	// EscapeHTML escapes <, > and & to embed JSON in HTML safely
+	EscapeHTML bool
+	// EscapeLineTerminators escapes U+2028 and U+2029,
+	// which are not allowed in JavaScript string literals
+	EscapeLineTerminators bool
+	// ASCII escapes all non-ASCII characters
+	ASCII bool
+	// InvalidUTF8 defines how invalid UTF-8 is handled
+	InvalidUTF8 InvalidUTF8
+}
+
+const hexDigits = "0123456789abcdef"
+
+// Append appends s encoded as JSON string to dst
+func (e *StringEncoder) Append(dst, s []byte) ([]byte, error) {
+	dst = append(dst, '"')
+	start := 0 // start of bytes which do not need escaping
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if !e.escapeASCII(c) {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = appendEscapedRune(dst, rune(c))
+			}
+			i++
+			start = i
+			continue
+		}
+		c, size := utf8.DecodeRune(s[i:])
+		switch {
+		case c == utf8.RuneError && size == 1:
+			if e.InvalidUTF8 == FailOnInvalidUTF8 {
+				return dst, ErrInvalidUTF8
+			}
+			dst = append(dst, s[start:i]...)
+			if e.ASCII {
+				dst = appendEscapedRune(dst, utf8.RuneError)
+			} else {
+				dst = appendRune(dst, utf8.RuneError)
+			}
+		case e.ASCII || (e.EscapeLineTerminators && (c == '\u2028' || c == '\u2029')):
+			dst = append(dst, s[start:i]...)
+			if c > 0xFFFF {
+				r1, r2 := utf16.EncodeRune(c)
+				dst = appendEscapedRune(appendEscapedRune(dst, r1), r2)
+			} else {
+				dst = appendEscapedRune(dst, c)
+			}
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"'), nil
+}
+
+func (e *StringEncoder) escapeASCII(c byte) bool {
+	switch {
+	case c < 0x20, c == '"', c == '\\':
+		return true
+	case e.EscapeHTML && (c == '<' || c == '>' || c == '&'):
+		return true
+	}
+	return false
+}
+
+// appendEscapedRune appends c from Basic Multilingual Plane as \uXXXX
+func appendEscapedRune(dst []byte, c rune) []byte {
+	return append(dst, '\\', 'u', hexDigits[c>>12&0xF], hexDigits[c>>8&0xF], hexDigits[c>>4&0xF], hexDigits[c&0xF])
+}
@@ -0,0 +1,86 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringEncoder_Append(t *testing.T) {
+	tests := []struct {
+		name    string
+		enc     StringEncoder
+		s       string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "plain",
+			s:    "hello",
+			want: `"hello"`,
+		},
+		{
+			name: "short escapes",
+			s:    "\"\\\b\f\n\r\t/",
+			want: `"\"\\\b\f\n\r\t/"`,
+		},
+		{
+			name: "control characters",
+			s:    "\x00\x07\x1f\x7f",
+			want: "\"\\u0000\\u0007\\u001f\x7f\"",
+		},
+		{
+			name: "UTF-8 is kept",
+			s:    "\u043f\u0440\U0001F600 <&>",
+			want: "\"\u043f\u0440\U0001F600 <&>\"",
+		},
+		{
+			name: "HTML",
+			enc:  StringEncoder{EscapeHTML: true},
+			s:    "<a&b>",
+			want: `"\u003ca\u0026b\u003e"`,
+		},
+		{
+			name: "line terminators",
+			enc:  StringEncoder{EscapeLineTerminators: true},
+			s:    "a\u2028b\u2029c",
+			want: `"a\u2028b\u2029c"`,
+		},
+		{
+			name: "ASCII",
+			enc:  StringEncoder{ASCII: true},
+			s:    "\u00e9\U0001F600",
+			want: `"\u00e9\ud83d\ude00"`,
+		},
+		{
+			name: "replace invalid UTF-8",
+			s:    "a\xffb",
+			want: "\"a\ufffdb\"",
+		},
+		{
+			name: "replace invalid UTF-8 with ASCII",
+			enc:  StringEncoder{ASCII: true},
+			s:    "a\xffb",
+			want: `"a\ufffdb"`,
+		},
+		{
+			name:    "fail on invalid UTF-8",
+			enc:     StringEncoder{InvalidUTF8: FailOnInvalidUTF8},
+			s:       "a\xffb",
+			wantErr: ErrInvalidUTF8,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.enc.Append(nil, []byte(tt.s))
+			if tt.wantErr != nil {
+				require.Equal(t, tt.wantErr, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(got))
+			require.True(t, json.Valid(got))
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package schema
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+var defaultMapLength = defaultArrayLength
+
+// Map is an object with generated keys
+type Map struct {
+	Length Length
+	Keys   *String
+	Values Node
+}
+
+func (m *Map) UnmarshalYAML(value *yaml.Node) error {
+	aux := struct {
+		Length Length  `yaml:"length"`
+		Keys   *String `yaml:"keys"`
+		Values *node   `yaml:"values"`
+	}{
+		Length: defaultMapLength,
+	}
+	if err := value.Decode(&aux); err != nil {
+		return err
+	}
+	if aux.Keys == nil {
+		return &yamlError{
+			line: value.Line,
+			err:  errors.New("\"keys\" is required"),
+		}
+	}
+	if aux.Values == nil {
+		return &yamlError{
+			line: value.Line,
+			err:  errors.New("\"values\" is required"),
+		}
+	}
+	*m = Map{
+		Length: aux.Length,
+		Keys:   aux.Keys,
+		Values: aux.Values.Node,
+	}
+	return nil
+}
+
+func (m *Map) GenerateJSON(ctx *Context, w io.Writer, r *rand.Rand) error {
+	keys, err := m.generateKeys(ctx, r, m.Length.Rand(r))
+	if err != nil {
+		return m.wrapKeysErr(err)
+	}
+	if ctx.SortKeys() {
+		sort.Strings(keys)
+	}
+	if _, err := w.Write([]byte{'{'}); err != nil {
+		return err
+	}
+	for i, k := range keys {
+		if i > 0 {
+			if _, err := w.Write([]byte{','}); err != nil {
+				return err
+			}
+		}
+		b, err := ctx.stringEncoder.Append(make([]byte, 0, len(k)+3), []byte(k))
+		if err != nil {
+			return m.wrapKeysErr(err)
+		}
+		if _, err := w.Write(append(b, ':')); err != nil {
+			return err
+		}
+		if err := m.Values.GenerateJSON(ctx, w, r); err != nil {
+			return WrapErr("."+k, err)
+		}
+	}
+	_, err = w.Write([]byte{'}'})
+	return err
+}
+
+// generateKeys returns n unique keys in order of generation
+func (m *Map) generateKeys(ctx *Context, r *rand.Rand, n uint64) ([]string, error) {
+	bs, err := sampleUnique(ctx, r, n, m.Keys.StringRander, "key", func() ([]byte, error) {
+		return m.Keys.Rand(ctx, r)
+	}, func(_ uint64, err error) error {
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(bs))
+	for _, b := range bs {
+		keys = append(keys, string(b))
+	}
+	return keys, nil
+}
+
+func (m *Map) Walk(fn WalkFn) error {
+	var errs Errors
+	errs.Add(m.wrapKeysErr(Walk(m.Keys, fn)))
+	errs.Add(WrapErr(".*", Walk(m.Values, fn)))
+	return errs.Err()
+}
+
+func (m *Map) wrapKeysErr(err error) error {
+	return WrapErr(".(keys)", err)
+}
@@ -0,0 +1,101 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMap_GenerateJSON(t *testing.T) {
+	var m Map
+	require.NoError(t, yaml.Unmarshal([]byte(`
+length: [3, 5]
+keys:
+  choices: [en, de, fr, es, ru]
+values:
+  type: int
+  range: [1, 1]
+`), &m))
+
+	for _, sortKeys := range []bool{false, true} {
+		ctx := NewContext()
+		ctx.SetSortKeys(sortKeys)
+		for seed := int64(0); seed < 10; seed++ {
+			var w bytes.Buffer
+			require.NoError(t, m.GenerateJSON(ctx, &w, rand.New(rand.NewSource(seed))))
+			var v map[string]int
+			require.NoError(t, json.Unmarshal(w.Bytes(), &v), w.String())
+			require.True(t, len(v) >= 3 && len(v) <= 5, w.String())
+			for k, n := range v {
+				assert.Contains(t, []string{"en", "de", "fr", "es", "ru"}, k)
+				assert.Equal(t, 1, n)
+			}
+			if sortKeys {
+				want, err := json.Marshal(v) // encoding/json sorts keys
+				require.NoError(t, err)
+				assert.Equal(t, string(want), w.String())
+			}
+		}
+	}
+}
+
+func TestMap_GenerateJSON_NotEnoughKeys(t *testing.T) {
+	m := Map{
+		Length: Length{Min: 3, Max: 3},
+		Keys:   &String{StringRander: StringChoices{"a", "b"}},
+		Values: Null{},
+	}
+	var w bytes.Buffer
+	assert.EqualError(t, m.GenerateJSON(NewContext(), &w, rand.New(rand.NewSource(1))),
+		".(keys): unable to generate 3 unique keys: there are only 2 distinct values")
+}
+
+func TestMap_GenerateJSON_AllKeys(t *testing.T) {
+	choices := make(StringChoices, 1000)
+	for i := range choices {
+		choices[i] = strconv.Itoa(i)
+	}
+	m := Map{
+		Length: Length{Min: 1000, Max: 1000},
+		Keys:   &String{StringRander: choices},
+		Values: Null{},
+	}
+	for seed := int64(0); seed < 20; seed++ {
+		var w bytes.Buffer
+		require.NoError(t, m.GenerateJSON(NewContext(), &w, rand.New(rand.NewSource(seed))), "seed %d", seed)
+		var v map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Bytes(), &v))
+		require.Len(t, v, 1000)
+	}
+}
+
+func TestMap_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name:    "no keys",
+			data:    "{values: int}",
+			wantErr: `line 1: "keys" is required`,
+		},
+		{
+			name:    "no values",
+			data:    "{keys: {fake: username}}",
+			wantErr: `line 1: "values" is required`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Map
+			assert.EqualError(t, yaml.Unmarshal([]byte(tt.data), &m), tt.wantErr)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -19,6 +20,14 @@ type Walker interface {
 	Walk(fn WalkFn) error
 }
 
+// Countable is implemented by nodes which can generate
+// only a finite number of distinct values
+type Countable interface {
+	// Count returns the maximum number of distinct values node can generate.
+	// It returns false if this number is unknown.
+	Count(*Context) (uint64, bool)
+}
+
 func Walk(n Node, fn WalkFn) error {
 	proceed, err := fn(n)
 	if !proceed {
@@ -33,12 +42,21 @@ func Walk(n Node, fn WalkFn) error {
 type nodeType string
 
 const (
-	boolType    nodeType = "bool"
-	integerType nodeType = "int"
-	floatType   nodeType = "float"
-	stringType  nodeType = "string"
-	arrayType   nodeType = "array"
-	objectType  nodeType = "object"
+	boolType     nodeType = "bool"
+	integerType  nodeType = "int"
+	floatType    nodeType = "float"
+	stringType   nodeType = "string"
+	arrayType    nodeType = "array"
+	objectType   nodeType = "object"
+	nullType     nodeType = "null"
+	constType    nodeType = "const"
+	oneOfType    nodeType = "oneOf"
+	sequenceType nodeType = "sequence"
+	dateTimeType nodeType = "datetime"
+	exprType     nodeType = "expr"
+	mapType      nodeType = "map"
+	tupleType    nodeType = "tuple"
+	bytesType    nodeType = "bytes"
 )
 
 // node is a helper type for unmarshal Node
@@ -47,6 +65,9 @@ type node struct {
 }
 
 func (n *node) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.AliasNode {
+		value = value.Alias
+	}
 	switch value.Kind {
 	case yaml.ScalarNode:
 		return n.unmarshalYAMLScalar(value)
@@ -60,11 +81,10 @@ func (n *node) UnmarshalYAML(value *yaml.Node) error {
 }
 
 func (n *node) unmarshalYAMLScalar(value *yaml.Node) error {
-	var typ nodeType
-	if err := value.Decode(&typ); err != nil {
-		return err
-	}
-	switch typ {
+	// value.Value is used instead of decoding to support inline null
+	switch typ := nodeType(value.Value); typ {
+	case nullType:
+		n.Node = Null{}
 	case boolType:
 		n.Node = &Bool{}
 	case integerType:
@@ -75,7 +95,20 @@ func (n *node) unmarshalYAMLScalar(value *yaml.Node) error {
 		n.Node = &Float{
 			Range: &defaultFloatRange,
 		}
-	case arrayType, objectType:
+	case sequenceType:
+		seq := defaultSequence
+		n.Node = &seq
+	case dateTimeType:
+		n.Node = &DateTime{
+			Range:  defaultTimeRange,
+			Format: RFC3339Format,
+		}
+	case bytesType:
+		n.Node = &Bytes{
+			Length:   defaultBytesLength,
+			Encoding: Base64Encoding,
+		}
+	case arrayType, objectType, constType, oneOfType, exprType, mapType, tupleType:
 		return &yamlError{
 			line: value.Line,
 			err:  fmt.Errorf("unable to unmarshal inline %q", typ),
@@ -91,20 +124,44 @@ func (n *node) unmarshalYAMLScalar(value *yaml.Node) error {
 
 func (n *node) unmarshalYAMLMapping(value *yaml.Node) error {
 	var aux struct {
-		Type nodeType `yaml:"type"`
+		// yaml.Node is used to support "type: null"
+		Type yaml.Node `yaml:"type"`
+		Ref  *string   `yaml:"ref"`
 	}
 	if err := value.Decode(&aux); err != nil {
 		return err
 	}
-	if aux.Type == "" {
+	typ := nodeType(aux.Type.Value)
+	if aux.Ref != nil {
+		if typ != "" {
+			return &yamlError{
+				line: value.Line,
+				err:  errors.New("reference should not specify type"),
+			}
+		}
+		// references to fields start with "../",
+		// others are references to definitions
+		if strings.HasPrefix(*aux.Ref, "../") {
+			n.Node = &FieldRef{}
+		} else {
+			n.Node = &Ref{}
+		}
+		return value.Decode(n.Node)
+	}
+	if typ == "" {
 		return &yamlError{
 			line: value.Line,
 			err:  errors.New("type is required"),
 		}
 	}
-	switch aux.Type {
+	switch typ {
+	case nullType:
+		n.Node = Null{}
+		return nil
+	case constType:
+		n.Node = &Const{}
 	case boolType:
-		n.Node = Bool{}
+		n.Node = &Bool{}
 	case integerType:
 		n.Node = &Integer{}
 	case floatType:
@@ -115,32 +172,73 @@ func (n *node) unmarshalYAMLMapping(value *yaml.Node) error {
 		n.Node = &Array{}
 	case objectType:
 		n.Node = &Object{}
+	case oneOfType:
+		n.Node = &OneOf{}
+	case sequenceType:
+		n.Node = &Sequence{}
+	case dateTimeType:
+		n.Node = &DateTime{}
+	case exprType:
+		n.Node = &Expr{}
+	case mapType:
+		n.Node = &Map{}
+	case tupleType:
+		n.Node = &Tuple{}
+	case bytesType:
+		n.Node = &Bytes{}
 	default:
 		return &yamlError{
 			line: value.Line,
-			err:  fmt.Errorf("unsupported type: %q", aux.Type),
+			err:  fmt.Errorf("unsupported type: %q", typ),
 		}
 	}
 	return value.Decode(n.Node)
 }
 
-// nodeMap is a helper type for unmarshal map[string]Node
-type nodeMap map[string]Node
+// fieldMap is a helper type for unmarshal map[string]*Field.
+// Unlike map, it keeps the order in which keys were defined.
+type fieldMap struct {
+	fields map[string]*Field
+	keys   []string
+}
 
-func (n *nodeMap) UnmarshalYAML(value *yaml.Node) error {
-	var aux map[string]*node
-	if err := value.Decode(&aux); err != nil {
-		return err
+func (m *fieldMap) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode {
+		return &yamlError{
+			line: value.Line,
+			err:  fmt.Errorf("mapping expected, got: %s", value.Tag),
+		}
 	}
-	*n = make(nodeMap, len(aux))
-	for k, v := range aux {
-		if v == nil {
+	*m = fieldMap{
+		fields: make(map[string]*Field, len(value.Content)/2),
+		keys:   make([]string, 0, len(value.Content)/2),
+	}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		var k string
+		if err := value.Content[i].Decode(&k); err != nil {
+			return err
+		}
+		if _, found := m.fields[k]; found {
 			return &yamlError{
-				line: value.Line,
+				line: value.Content[i].Line,
+				err:  fmt.Errorf("key %q already defined", k),
+			}
+		}
+		v := value.Content[i+1]
+		if v.ShortTag() == "!!null" && v.Value == "" {
+			return &yamlError{
+				line: v.Line,
 				err:  errors.New("empty node"),
 			}
 		}
-		(*n)[k] = v.Node
+		// UnmarshalYAML is called directly, because
+		// v.Decode skips nulls, but they are valid inline nodes
+		f := new(Field)
+		if err := f.UnmarshalYAML(v); err != nil {
+			return err
+		}
+		m.fields[k] = f
+		m.keys = append(m.keys, k)
 	}
 	return nil
 }
@@ -0,0 +1,24 @@
+package schema
+
+import (
+	"io"
+	"math/rand"
+)
+
+var nullJSON = []byte("null")
+
+// Null always generates null
+type Null struct{}
+
+func (Null) GenerateJSON(_ *Context, w io.Writer, _ *rand.Rand) error {
+	_, err := w.Write(nullJSON)
+	return err
+}
+
+func (Null) Count(*Context) (uint64, bool) {
+	return 1, true
+}
+
+func (Null) Enumerate(*Context) ([][]byte, bool, error) {
+	return [][]byte{nullJSON}, true, nil
+}
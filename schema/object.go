@@ -1,18 +1,137 @@
 package schema
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"math/rand"
 	"sort"
-	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
+// Field is a field of Object
+type Field struct {
+	Node
+	// Nullable is a probability for field to be null
+	Nullable Probability
+	// Optional is a probability for field to be omitted
+	Optional Probability
+	// If is a condition for field to be generated with Node.
+	// Otherwise, Else is generated or the field is omitted if Else is nil.
+	If   *Expr
+	Else Node
+}
+
+func (f *Field) UnmarshalYAML(value *yaml.Node) error {
+	*f = Field{}
+	if value.Kind == yaml.MappingNode {
+		var aux struct {
+			Type     yaml.Node   `yaml:"type"`
+			Nullable Probability `yaml:"nullable"`
+			Optional Probability `yaml:"optional"`
+			If       yaml.Node   `yaml:"if"`
+			Then     *node       `yaml:"then"`
+			Else     *node       `yaml:"else"`
+		}
+		if err := value.Decode(&aux); err != nil {
+			return err
+		}
+		f.Nullable, f.Optional = aux.Nullable, aux.Optional
+		if aux.If.Kind != 0 {
+			var err error
+			if f.If, err = unmarshalExpr(&aux.If); err != nil {
+				return err
+			}
+		}
+		if f.If == nil && (aux.Then != nil || aux.Else != nil) {
+			return &yamlError{
+				line: value.Line,
+				err:  errors.New("\"then\" and \"else\" require \"if\""),
+			}
+		}
+		if aux.Else != nil {
+			f.Else = aux.Else.Node
+		}
+		if aux.Then != nil {
+			if aux.Type.Kind != 0 {
+				return &yamlError{
+					line: value.Line,
+					err:  errors.New("field with \"then\" should not specify type"),
+				}
+			}
+			f.Node = aux.Then.Node
+			return nil
+		}
+	}
+	var n node
+	if err := n.UnmarshalYAML(value); err != nil {
+		return err
+	}
+	f.Node = n.Node
+	return nil
+}
+
+// choose returns the node to generate, or nil if the field should be omitted
+func (f *Field) choose(ctx *Context, r *rand.Rand) (Node, error) {
+	if f.Optional.Happens(r) {
+		return nil, nil
+	}
+	if f.If == nil {
+		return f.Node, nil
+	}
+	v, err := f.If.Eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if truthy(v) {
+		return f.Node, nil
+	}
+	return f.Else, nil
+}
+
+func (f *Field) GenerateJSON(ctx *Context, w io.Writer, r *rand.Rand) error {
+	return f.generate(ctx, w, r, f.Node)
+}
+
+func (f *Field) generate(ctx *Context, w io.Writer, r *rand.Rand, n Node) error {
+	if f.Nullable.Happens(r) {
+		_, err := w.Write(nullJSON)
+		return err
+	}
+	return n.GenerateJSON(ctx, w, r)
+}
+
+// refPaths returns references made by field
+func (f *Field) refPaths() []RefPath {
+	refs := nodeRefs(f.Node)
+	if f.If != nil {
+		refs = append(refs, f.If.refPaths()...)
+	}
+	if f.Else != nil {
+		refs = append(refs, nodeRefs(f.Else)...)
+	}
+	return refs
+}
+
 type Object struct {
-	Fields     map[string]Node
+	Fields map[string]*Field
+	// keys is an order of fields as they were defined in schema
+	keys       []string
 	sortedKeys []string
+	// order is an order in which fields are generated, so that referred fields
+	// are generated before fields referring to them. It is nil if fields do
+	// not refer to each other, so they are generated in order of writing.
+	order []string
+	// referred are fields which values are referred by other fields
+	referred map[string]bool
+	// outerRefs are references of fields to fields of ancestor objects,
+	// relative to the field which this object is a value of
+	outerRefs []RefPath
+	// err is an error in references between fields, it is reported by Schema.Validate
+	err error
 }
 
 func (o *Object) sortKeys() {
@@ -27,9 +146,22 @@ func (o *Object) sorted() bool {
 	return o.sortedKeys != nil
 }
 
+// fieldOrder returns keys of fields in order they should be written.
+// If keys are not sorted, they are written in order of definition,
+// so the output is deterministic in both cases.
+func (o *Object) fieldOrder(sortKeys bool) []string {
+	if sortKeys || o.keys == nil {
+		if !o.sorted() {
+			o.sortKeys()
+		}
+		return o.sortedKeys
+	}
+	return o.keys
+}
+
 func (o *Object) UnmarshalYAML(value *yaml.Node) error {
 	var aux struct {
-		Fields nodeMap `yaml:"fields"`
+		Fields *fieldMap `yaml:"fields"`
 	}
 	if err := value.Decode(&aux); err != nil {
 		return err
@@ -41,59 +173,204 @@ func (o *Object) UnmarshalYAML(value *yaml.Node) error {
 		}
 	}
 	*o = Object{
-		Fields: aux.Fields,
+		Fields: aux.Fields.fields,
+		keys:   aux.Fields.keys,
 	}
+	o.sortKeys()
+	o.resolveRefs()
 	return nil
 }
 
+// resolveRefs finds references between fields and the order of generation
+func (o *Object) resolveRefs() {
+	var errs Errors
+	deps := make(map[string][]string)
+	for _, k := range o.fieldOrder(false) {
+		if ref, ok := o.Fields[k].Node.(*FieldRef); ok {
+			ref.setEpoch(o.Fields)
+		}
+		for _, p := range o.Fields[k].refPaths() {
+			if p.Up > 1 {
+				o.outerRefs = append(o.outerRefs, RefPath{
+					Up:    p.Up - 1,
+					Names: p.Names,
+				})
+				continue
+			}
+			name := p.Names[0]
+			if _, found := o.Fields[name]; !found {
+				errs.Add(o.wrapErr(k, fmt.Errorf("reference to unknown field: %q", name)))
+				continue
+			}
+			if o.referred == nil {
+				o.referred = make(map[string]bool)
+			}
+			o.referred[name] = true
+			deps[k] = append(deps[k], name)
+		}
+	}
+	if len(deps) > 0 {
+		var err error
+		o.order, err = o.orderByDeps(deps)
+		errs.Add(err)
+	}
+	o.err = errs.Err()
+}
+
+// orderByDeps returns fields in order of definition, but with
+// fields from deps moved before fields which depend on them
+func (o *Object) orderByDeps(deps map[string][]string) ([]string, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	order := make([]string, 0, len(o.Fields))
+	state := make(map[string]int, len(o.Fields))
+	var path []string
+	var visit func(field string) error
+	visit = func(field string) error {
+		switch state[field] {
+		case visiting:
+			for i, f := range path {
+				if f == field {
+					return fmt.Errorf("fields refer to each other: %s",
+						strings.Join(append(path[i:], field), " -> "))
+				}
+			}
+		case visited:
+			return nil
+		}
+		state[field] = visiting
+		path = append(path, field)
+		for _, dep := range deps[field] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[field] = visited
+		order = append(order, field)
+		return nil
+	}
+	for _, k := range o.fieldOrder(false) {
+		if err := visit(k); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
 func (o *Object) GenerateJSON(ctx *Context, w io.Writer, r *rand.Rand) error {
+	if o.err != nil {
+		return o.err
+	}
+	var s scope
+	if o.referred != nil {
+		s = make(scope, len(o.referred))
+	}
+	ctx.pushScope(s)
+	defer ctx.popScope()
+	if o.order != nil {
+		return o.generateOrdered(ctx, w, r, s)
+	}
+
 	if _, err := w.Write([]byte{'{'}); err != nil {
 		return err
 	}
-	// TODO: sort when creating
-	if ctx.SortKeys() {
-		if !o.sorted() {
-			o.sortKeys()
+	var wasFirst bool
+	for _, key := range o.fieldOrder(ctx.SortKeys()) {
+		field := o.Fields[key]
+		n, err := field.choose(ctx, r)
+		if err != nil {
+			return o.wrapErr(key, err)
 		}
-		for i, key := range o.sortedKeys {
-			if i > 0 {
-				if _, err := w.Write([]byte{','}); err != nil {
-					return err
-				}
-			}
-			if err := o.writeField(ctx, w, r, key, o.Fields[key]); err != nil {
-				return o.wrapErr(key, err)
-			}
+		if n == nil {
+			continue
 		}
-	} else {
-		var wasFirst bool
-		for field, node := range o.Fields {
-			if wasFirst {
-				if _, err := w.Write([]byte{','}); err != nil {
-					return err
-				}
+		if wasFirst {
+			if _, err := w.Write([]byte{','}); err != nil {
+				return err
 			}
-			wasFirst = true
-			if err := o.writeField(ctx, w, r, field, node); err != nil {
-				return o.wrapErr(field, err)
+		}
+		wasFirst = true
+		if err := o.writeKey(ctx, w, key); err != nil {
+			return o.wrapErr(key, err)
+		}
+		if err := field.generate(ctx, w, r, n); err != nil {
+			return o.wrapErr(key, err)
+		}
+	}
+	_, err := w.Write([]byte{'}'})
+	return err
+}
+
+// generateOrdered generates fields in order of dependencies
+// and then writes them in the right order
+func (o *Object) generateOrdered(ctx *Context, w io.Writer, r *rand.Rand, s scope) error {
+	values := make(map[string][]byte, len(o.Fields))
+	for _, key := range o.order {
+		field := o.Fields[key]
+		n, err := field.choose(ctx, r)
+		if err != nil {
+			return o.wrapErr(key, err)
+		}
+		if n == nil {
+			continue
+		}
+		var b bytes.Buffer
+		if err := field.generate(ctx, &b, r, n); err != nil {
+			return o.wrapErr(key, err)
+		}
+		values[key] = b.Bytes()
+		if o.referred[key] {
+			s[key] = b.Bytes()
+		}
+	}
+
+	if _, err := w.Write([]byte{'{'}); err != nil {
+		return err
+	}
+	var wasFirst bool
+	for _, key := range o.fieldOrder(ctx.SortKeys()) {
+		v, ok := values[key]
+		if !ok {
+			continue
+		}
+		if wasFirst {
+			if _, err := w.Write([]byte{','}); err != nil {
+				return err
 			}
 		}
+		wasFirst = true
+		if err := o.writeKey(ctx, w, key); err != nil {
+			return o.wrapErr(key, err)
+		}
+		if _, err := w.Write(v); err != nil {
+			return err
+		}
 	}
 	_, err := w.Write([]byte{'}'})
 	return err
 }
 
-func (o *Object) writeField(ctx *Context, w io.Writer, r *rand.Rand, field string, node Node) error {
-	if _, err := w.Write(append(strconv.AppendQuote(make([]byte, 0, 3*len(field)/2), field), ':')); err != nil {
+func (o *Object) writeKey(ctx *Context, w io.Writer, field string) error {
+	key, err := ctx.stringEncoder.Append(make([]byte, 0, len(field)+3), []byte(field))
+	if err != nil {
 		return err
 	}
-	return node.GenerateJSON(ctx, w, r)
+	_, err = w.Write(append(key, ':'))
+	return err
 }
 
 func (o *Object) Walk(fn WalkFn) error {
 	var errs Errors
-	for k, n := range o.Fields {
-		errs.Add(o.wrapErr(k, Walk(n, fn)))
+	for _, k := range o.fieldOrder(false) {
+		f := o.Fields[k]
+		errs.Add(o.wrapErr(k, Walk(f.Node, fn)))
+		if f.Else != nil {
+			errs.Add(o.wrapErr(k+"(else)", Walk(f.Else, fn)))
+		}
 	}
 	return errs.Err()
 }
@@ -0,0 +1,155 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestObject_GenerateJSON(t *testing.T) {
+	var o Object
+	require.NoError(t, yaml.Unmarshal([]byte(`
+fields:
+  c: {type: int, choices: [1]}
+  a: {type: int, choices: [2]}
+  b: {type: int, choices: [3]}
+`), &o))
+	tests := []struct {
+		name     string
+		sortKeys bool
+		wantW    string
+	}{
+		{
+			name:     "sorted",
+			sortKeys: true,
+			wantW:    `{"a":2,"b":3,"c":1}`,
+		},
+		{
+			name:     "order of definition",
+			sortKeys: false,
+			wantW:    `{"c":1,"a":2,"b":3}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w bytes.Buffer
+			ctx := NewContext()
+			ctx.SetSortKeys(tt.sortKeys)
+			require.NoError(t, o.GenerateJSON(ctx, &w, rand.New(rand.NewSource(1))))
+			require.Equal(t, tt.wantW, w.String())
+		})
+	}
+}
+
+func TestObject_GenerateJSON_NullableOptional(t *testing.T) {
+	var o Object
+	require.NoError(t, yaml.Unmarshal([]byte(`
+fields:
+  a: {type: int, choices: [1], optional: 1}
+  b: {type: int, choices: [2], nullable: 1}
+  c: {type: int, choices: [3], optional: 1}
+  d: {type: int, choices: [4]}
+  e: {type: int, choices: [5], optional: 1}
+`), &o))
+	var w bytes.Buffer
+	require.NoError(t, o.GenerateJSON(NewContext(), &w, rand.New(rand.NewSource(1))))
+	require.Equal(t, `{"b":null,"d":4}`, w.String())
+}
+
+func TestField_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name: "inline",
+			data: "int",
+		},
+		{
+			name: "nullable and optional",
+			data: "{type: int, nullable: 0.5, optional: 0.1}",
+		},
+		{
+			name:    "probability > 1",
+			data:    "{type: int, nullable: 2}",
+			wantErr: true,
+		},
+		{
+			name:    "negative probability",
+			data:    "{type: int, optional: -0.5}",
+			wantErr: true,
+		},
+		{
+			name: "if then else",
+			data: "{if: a > 1, then: int, else: {type: float}}",
+		},
+		{
+			name:    "then without if",
+			data:    "{then: int}",
+			wantErr: true,
+		},
+		{
+			name:    "then with type",
+			data:    "{if: a, type: int, then: int}",
+			wantErr: true,
+		},
+		{
+			name:    "invalid condition",
+			data:    "{if: a >, type: int}",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f Field
+			err := yaml.Unmarshal([]byte(tt.data), &f)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestObject_GenerateJSON_Conditional(t *testing.T) {
+	var o Object
+	require.NoError(t, yaml.Unmarshal([]byte(`
+fields:
+  card:
+    if: method == "card"
+    type: object
+    fields:
+      number: {type: string, fake: creditCard}
+  iban:
+    if: method == "bank"
+    type: string
+    fake: iban
+  fee:
+    if: method == "card"
+    then: {type: const, value: 1}
+    else: {type: const, value: 0}
+  method: {type: string, choices: [card, bank]}
+`), &o))
+
+	methods := make(map[string]bool)
+	for seed := int64(0); seed < 20; seed++ {
+		var w bytes.Buffer
+		require.NoError(t, o.GenerateJSON(NewContext(), &w, rand.New(rand.NewSource(seed))))
+		var v map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Bytes(), &v))
+		method := v["method"].(string)
+		methods[method] = true
+		_, hasCard := v["card"]
+		_, hasIBAN := v["iban"]
+		require.Equal(t, method == "card", hasCard, w.String())
+		require.Equal(t, method == "bank", hasIBAN, w.String())
+		require.Equal(t, method == "card", v["fee"] == 1.0, w.String())
+	}
+	require.Len(t, methods, 2)
+}
@@ -0,0 +1,147 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OneOf generates one of its nodes chosen according to their weights
+type OneOf struct {
+	Nodes []Node
+	// weights are nil when nodes are equally likely
+	weights *weights
+}
+
+// NewOneOf returns OneOf choosing its nodes with given weights.
+// If ws is nil, nodes are equally likely.
+func NewOneOf(nodes []Node, ws []float64) (*OneOf, error) {
+	if len(nodes) == 0 {
+		return nil, errors.New("at least one node is required")
+	}
+	o := &OneOf{
+		Nodes: nodes,
+	}
+	if ws == nil {
+		return o, nil
+	}
+	if len(ws) != len(nodes) {
+		return nil, fmt.Errorf("number of weights (%d) does not match number of nodes (%d)", len(ws), len(nodes))
+	}
+	t, err := newWeights(ws)
+	if err != nil {
+		return nil, err
+	}
+	if !uniform(ws) {
+		o.weights = t
+	}
+	return o, nil
+}
+
+func (o *OneOf) UnmarshalYAML(value *yaml.Node) error {
+	var aux struct {
+		Nodes yaml.Node `yaml:"nodes"`
+	}
+	if err := value.Decode(&aux); err != nil {
+		return err
+	}
+	if aux.Nodes.Kind != yaml.SequenceNode {
+		return &yamlError{
+			line: value.Line,
+			err:  errors.New("\"nodes\" should be a sequence of nodes"),
+		}
+	}
+	nodes := make([]Node, 0, len(aux.Nodes.Content))
+	ws := make([]float64, 0, len(aux.Nodes.Content))
+	for _, v := range aux.Nodes.Content {
+		var n node
+		// UnmarshalYAML is called directly to support inline null
+		if err := n.UnmarshalYAML(v); err != nil {
+			return err
+		}
+		weight := 1.0
+		if v.Kind == yaml.MappingNode {
+			var w struct {
+				Weight *float64 `yaml:"weight"`
+			}
+			if err := v.Decode(&w); err != nil {
+				return err
+			}
+			if w.Weight != nil {
+				weight = *w.Weight
+			}
+		}
+		nodes = append(nodes, n.Node)
+		ws = append(ws, weight)
+	}
+	oneOf, err := NewOneOf(nodes, ws)
+	if err != nil {
+		return &yamlError{
+			line: value.Line,
+			err:  err,
+		}
+	}
+	*o = *oneOf
+	return nil
+}
+
+func (o *OneOf) GenerateJSON(ctx *Context, w io.Writer, r *rand.Rand) error {
+	i := o.weights.Index(r, len(o.Nodes))
+	return o.wrapIndexErr(i, o.Nodes[i].GenerateJSON(ctx, w, r))
+}
+
+func (o *OneOf) Walk(fn WalkFn) error {
+	var errs Errors
+	for i, n := range o.Nodes {
+		errs.Add(o.wrapIndexErr(i, Walk(n, fn)))
+	}
+	return errs.Err()
+}
+
+func (o *OneOf) wrapIndexErr(ind int, err error) error {
+	return WrapErr("("+strconv.Itoa(ind)+")", err)
+}
+
+func (o *OneOf) Count(ctx *Context) (uint64, bool) {
+	var sum uint64
+	for i, n := range o.Nodes {
+		if !o.weights.reachable(i) {
+			continue
+		}
+		c, ok := n.(Countable)
+		if !ok {
+			return 0, false
+		}
+		cnt, ok := c.Count(ctx)
+		if !ok || sum+cnt < sum {
+			return 0, false
+		}
+		sum += cnt
+	}
+	return sum, true
+}
+
+// Enumerate returns distinct values of reachable nodes
+func (o *OneOf) Enumerate(ctx *Context) ([][]byte, bool, error) {
+	var vs [][]byte
+	seen := make(map[string]struct{})
+	for i, n := range o.Nodes {
+		if !o.weights.reachable(i) {
+			continue
+		}
+		e, ok := n.(Enumerable)
+		if !ok {
+			return nil, false, nil
+		}
+		nvs, ok, err := e.Enumerate(ctx)
+		if !ok || err != nil {
+			return nil, false, o.wrapIndexErr(i, err)
+		}
+		vs = appendDistinct(vs, seen, nvs...)
+	}
+	return vs, true, nil
+}
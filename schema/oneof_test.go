@@ -0,0 +1,49 @@
+package schema
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type errNode struct{}
+
+func (errNode) GenerateJSON(*Context, io.Writer, *rand.Rand) error {
+	return errors.New("error")
+}
+
+func TestOneOf_GenerateJSON(t *testing.T) {
+	o, err := NewOneOf([]Node{testNode("a"), testNode("b")}, []float64{0, 1})
+	require.NoError(t, err)
+	var w bytes.Buffer
+	for i := 0; i < 10; i++ {
+		require.NoError(t, o.GenerateJSON(nil, &w, rand.New(rand.NewSource(int64(i)))))
+	}
+	require.Equal(t, "bbbbbbbbbb", w.String())
+
+	o, err = NewOneOf([]Node{testNode("a"), errNode{}}, []float64{0, 1})
+	require.NoError(t, err)
+	require.EqualError(t, o.GenerateJSON(nil, &w, rand.New(rand.NewSource(1))), "(1): error")
+}
+
+func TestOneOf_UnmarshalYAML(t *testing.T) {
+	var s Schema
+	require.NoError(t, yaml.Unmarshal([]byte(`
+root:
+  type: object
+  fields:
+    f:
+      type: oneOf
+      nodes:
+        - null
+        - type: string
+          from: undefined
+          weight: 2
+`), &s))
+	require.EqualError(t, s.Validate(), `.f(1): undefined file: "undefined"`)
+}
@@ -0,0 +1,211 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"regexp/syntax"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+const defaultMaxRepeat = 10
+
+// StringPattern generates strings matching regular expression
+type StringPattern struct {
+	pattern string
+	gen     patternFunc
+}
+
+// patternFunc appends generated string to b
+type patternFunc func(b []byte, r *rand.Rand) []byte
+
+// NewStringPattern returns StringPattern for regular expression in syntax of
+// regexp package. Unbounded repetitions (e.g. * and +) are limited to maxRepeat.
+func NewStringPattern(pattern string, maxRepeat int) (*StringPattern, error) {
+	if maxRepeat < 0 {
+		return nil, errors.New("max repeat should be non-negative")
+	}
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	gen, err := compilePattern(re, maxRepeat)
+	if err != nil {
+		return nil, err
+	}
+	return &StringPattern{
+		pattern: pattern,
+		gen:     gen,
+	}, nil
+}
+
+func (p *StringPattern) Pattern() string {
+	return p.pattern
+}
+
+func (p *StringPattern) Rand(_ *Context, r *rand.Rand) ([]byte, error) {
+	return p.gen(nil, r), nil
+}
+
+func compilePattern(re *syntax.Regexp, maxRepeat int) (patternFunc, error) {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return nil, errors.New("pattern matches nothing")
+	case syntax.OpEmptyMatch, syntax.OpBeginLine, syntax.OpEndLine,
+		syntax.OpBeginText, syntax.OpEndText, syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return func(b []byte, _ *rand.Rand) []byte {
+			return b
+		}, nil
+	case syntax.OpLiteral:
+		runes, foldCase := re.Rune, re.Flags&syntax.FoldCase != 0
+		return func(b []byte, r *rand.Rand) []byte {
+			for _, c := range runes {
+				if foldCase && r.Intn(2) == 1 {
+					c = unicode.SimpleFold(c)
+				}
+				b = appendRune(b, c)
+			}
+			return b
+		}, nil
+	case syntax.OpCharClass:
+		class := newRuneClass(re.Rune)
+		if class.size() == 0 {
+			return nil, errors.New("pattern has empty character class")
+		}
+		return func(b []byte, r *rand.Rand) []byte {
+			return appendRune(b, class.Rand(r))
+		}, nil
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		// any character is limited to printable ASCII
+		class := newRuneClass([]rune{' ', '~'})
+		return func(b []byte, r *rand.Rand) []byte {
+			return appendRune(b, class.Rand(r))
+		}, nil
+	case syntax.OpCapture:
+		return compilePattern(re.Sub[0], maxRepeat)
+	case syntax.OpStar:
+		return compileRepeat(re.Sub[0], 0, maxRepeat, maxRepeat)
+	case syntax.OpPlus:
+		return compileRepeat(re.Sub[0], 1, 1+maxRepeat, maxRepeat)
+	case syntax.OpQuest:
+		return compileRepeat(re.Sub[0], 0, 1, maxRepeat)
+	case syntax.OpRepeat:
+		max := re.Max
+		if max == -1 {
+			max = re.Min + maxRepeat
+		}
+		return compileRepeat(re.Sub[0], re.Min, max, maxRepeat)
+	case syntax.OpConcat:
+		subs, err := compilePatterns(re.Sub, maxRepeat)
+		if err != nil {
+			return nil, err
+		}
+		return func(b []byte, r *rand.Rand) []byte {
+			for _, sub := range subs {
+				b = sub(b, r)
+			}
+			return b
+		}, nil
+	case syntax.OpAlternate:
+		subs, err := compilePatterns(re.Sub, maxRepeat)
+		if err != nil {
+			return nil, err
+		}
+		return func(b []byte, r *rand.Rand) []byte {
+			return subs[r.Intn(len(subs))](b, r)
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported pattern: %s", re)
+}
+
+func compilePatterns(res []*syntax.Regexp, maxRepeat int) ([]patternFunc, error) {
+	fs := make([]patternFunc, 0, len(res))
+	for _, re := range res {
+		f, err := compilePattern(re, maxRepeat)
+		if err != nil {
+			return nil, err
+		}
+		fs = append(fs, f)
+	}
+	return fs, nil
+}
+
+func compileRepeat(re *syntax.Regexp, min, max, maxRepeat int) (patternFunc, error) {
+	sub, err := compilePattern(re, maxRepeat)
+	if err != nil {
+		return nil, err
+	}
+	return func(b []byte, r *rand.Rand) []byte {
+		n := min
+		if max > min {
+			n += r.Intn(max - min + 1)
+		}
+		for i := 0; i < n; i++ {
+			b = sub(b, r)
+		}
+		return b
+	}, nil
+}
+
+func appendRune(b []byte, c rune) []byte {
+	var buf [utf8.UTFMax]byte
+	return append(b, buf[:utf8.EncodeRune(buf[:], c)]...)
+}
+
+// runeClass is a set of runes to choose from uniformly
+type runeClass struct {
+	ranges []runeRange
+	// cum are cumulative sizes of ranges
+	cum []int
+}
+
+type runeRange struct {
+	lo, hi rune
+}
+
+// newRuneClass returns runeClass from pairs of inclusive bounds
+// of ranges. Surrogates are excluded, since they are invalid runes.
+func newRuneClass(pairs []rune) runeClass {
+	var c runeClass
+	add := func(lo, hi rune) {
+		if lo > hi {
+			return
+		}
+		c.ranges = append(c.ranges, runeRange{lo: lo, hi: hi})
+		c.cum = append(c.cum, c.size()+int(hi-lo)+1)
+	}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		lo, hi := pairs[i], pairs[i+1]
+		if lo <= surrogateMax && hi >= surrogateMin {
+			add(lo, surrogateMin-1)
+			add(surrogateMax+1, hi)
+			continue
+		}
+		add(lo, hi)
+	}
+	return c
+}
+
+const (
+	surrogateMin = 0xD800
+	surrogateMax = 0xDFFF
+)
+
+func (c runeClass) size() int {
+	if len(c.cum) == 0 {
+		return 0
+	}
+	return c.cum[len(c.cum)-1]
+}
+
+func (c runeClass) Rand(r *rand.Rand) rune {
+	i := r.Intn(c.size())
+	j := sort.SearchInts(c.cum, i+1)
+	var from int
+	if j > 0 {
+		from = c.cum[j-1]
+	}
+	return c.ranges[j].lo + rune(i-from)
+}
@@ -0,0 +1,57 @@
+package schema
+
+import (
+	"math/rand"
+	"regexp"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringPattern_Rand(t *testing.T) {
+	patterns := []string{
+		`[A-Z]{3}-\d{4}`,
+		`\+7 \(\d{3}\) \d{3}-\d{2}-\d{2}`,
+		`(foo|bar)+baz?`,
+		`(?i)hello`,
+		`[^a-z]*`,
+		`\pL{2,5}`,
+		`a.c`,
+		`^$`,
+	}
+	r := rand.New(rand.NewSource(1))
+	for _, pattern := range patterns {
+		t.Run(pattern, func(t *testing.T) {
+			p, err := NewStringPattern(pattern, defaultMaxRepeat)
+			require.NoError(t, err)
+			re := regexp.MustCompile("^(?:" + pattern + ")$")
+			for i := 0; i < 100; i++ {
+				s, err := p.Rand(nil, r)
+				require.NoError(t, err)
+				assert.True(t, utf8.Valid(s), "%q is invalid UTF-8", s)
+				assert.Regexp(t, re, string(s))
+			}
+		})
+	}
+}
+
+func TestStringPattern_MaxRepeat(t *testing.T) {
+	p, err := NewStringPattern(`a*b+`, 3)
+	require.NoError(t, err)
+	re := regexp.MustCompile(`^a{0,3}b{1,4}$`)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		s, err := p.Rand(nil, r)
+		require.NoError(t, err)
+		assert.Regexp(t, re, string(s))
+	}
+}
+
+func TestNewStringPattern_Invalid(t *testing.T) {
+	_, err := NewStringPattern(`[a-`, defaultMaxRepeat)
+	require.Error(t, err)
+	_, err = NewStringPattern(`[^\x00-\x{10FFFF}]`, defaultMaxRepeat)
+	require.Error(t, err)
+}
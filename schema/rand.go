@@ -0,0 +1,39 @@
+package schema
+
+import (
+	"fmt"
+	"math/rand"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Probability is a probability of an event, it is in [0, 1]
+type Probability float64
+
+// Happens reports whether an event with probability p happened.
+// It does not consume random numbers for probabilities 0 and 1,
+// so adding them to schema does not change the rest of output.
+func (p Probability) Happens(r *rand.Rand) bool {
+	switch {
+	case p <= 0:
+		return false
+	case p >= 1:
+		return true
+	}
+	return r.Float64() < float64(p)
+}
+
+func (p *Probability) UnmarshalYAML(value *yaml.Node) error {
+	var f float64
+	if err := value.Decode(&f); err != nil {
+		return err
+	}
+	if f < 0 || f > 1 {
+		return &yamlError{
+			line: value.Line,
+			err:  fmt.Errorf("probability should be in [0, 1], got: %v", f),
+		}
+	}
+	*p = Probability(f)
+	return nil
+}
@@ -0,0 +1,146 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Ref is a reference to a node from definitions of Schema
+type Ref struct {
+	Name string
+	// MaxDepth limits the number of nested expansions of the definition
+	// made by references with MaxDepth. 0 means no limit.
+	MaxDepth uint
+	// Fallback is generated instead of definition when MaxDepth is reached
+	Fallback Node
+	// Node is the definition. It is set when Schema is unmarshaled.
+	Node Node
+}
+
+func (r *Ref) UnmarshalYAML(value *yaml.Node) error {
+	var aux struct {
+		Name     string `yaml:"ref"`
+		MaxDepth uint   `yaml:"maxDepth"`
+		Fallback *node  `yaml:"fallback"`
+	}
+	if err := value.Decode(&aux); err != nil {
+		return err
+	}
+	if aux.Name == "" {
+		return &yamlError{
+			line: value.Line,
+			err:  errors.New("\"ref\" should not be empty"),
+		}
+	}
+	*r = Ref{
+		Name:     aux.Name,
+		MaxDepth: aux.MaxDepth,
+		Fallback: Null{},
+	}
+	if aux.Fallback != nil {
+		r.Fallback = aux.Fallback.Node
+	}
+	return nil
+}
+
+func (r *Ref) GenerateJSON(ctx *Context, w io.Writer, rnd *rand.Rand) error {
+	if r.Node == nil {
+		return fmt.Errorf("undefined definition: %q", r.Name)
+	}
+	if r.MaxDepth > 0 {
+		if ctx.refDepths[r.Name] >= r.MaxDepth {
+			return r.Fallback.GenerateJSON(ctx, w, rnd)
+		}
+		ctx.refDepths[r.Name]++
+		defer func() { ctx.refDepths[r.Name]-- }()
+	}
+	return r.Node.GenerateJSON(ctx, w, rnd)
+}
+
+// Walk walks only through fallback, since the definition
+// is walked separately and can refer to itself
+func (r *Ref) Walk(fn WalkFn) error {
+	return Walk(r.Fallback, fn)
+}
+
+// resolveRefs sets definitions to all references found in n
+func resolveRefs(n Node, defs map[string]Node) {
+	_ = Walk(n, func(n Node) (bool, error) {
+		if r, ok := n.(*Ref); ok {
+			r.Node = defs[r.Name]
+		}
+		return true, nil
+	})
+}
+
+// checkRefCycles returns an error if there are definitions which
+// refer to themselves only through references without MaxDepth,
+// so their generation would never end
+func checkRefCycles(defs map[string]Node) error {
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	unbounded := make(map[string][]string, len(defs))
+	for _, name := range names {
+		_ = Walk(defs[name], func(n Node) (bool, error) {
+			if r, ok := n.(*Ref); ok && r.MaxDepth == 0 {
+				unbounded[name] = append(unbounded[name], r.Name)
+			}
+			return true, nil
+		})
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(defs))
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			for i, n := range path {
+				if n == name {
+					return fmt.Errorf("definitions refer to each other without maxDepth: %s",
+						strings.Join(append(path[i:], name), " -> "))
+				}
+			}
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, to := range unbounded[name] {
+			if err := visit(to); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+
+	var errs Errors
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			errs.Add(err)
+			// do not report the same cycle again
+			for _, n := range path {
+				state[n] = visited
+			}
+			path = path[:0]
+		}
+	}
+	return errs.Err()
+}
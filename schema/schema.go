@@ -5,36 +5,96 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Schema struct {
-	Files map[string]*struct{} `yaml:"files"` // pointer because it is
-	Root  Node                 `yaml:"root"`
+	Files       map[string]*struct{} `yaml:"files"` // pointer because it is
+	Definitions map[string]Node      `yaml:"definitions"`
+	Root        Node                 `yaml:"root"`
 }
 
 func (s *Schema) UnmarshalYAML(value *yaml.Node) error {
 	var aux struct {
-		Files map[string]*struct{} `yaml:"files"` // pointer because it is
-		Root  *node                `yaml:"root"`
+		Files       map[string]*struct{} `yaml:"files"` // pointer because it is
+		Definitions yaml.Node            `yaml:"definitions"`
+		Root        yaml.Node            `yaml:"root"`
 	}
 	if err := value.Decode(&aux); err != nil {
 		return err
 	}
-	if aux.Root == nil {
+	if aux.Root.Kind == 0 || aux.Root.ShortTag() == "!!null" && aux.Root.Value == "" {
 		return &yamlError{
 			line: value.Line,
 			err:  errors.New("root is not defined"),
 		}
 	}
+	// UnmarshalYAML is called directly to support inline null root
+	var root node
+	if err := root.UnmarshalYAML(&aux.Root); err != nil {
+		return err
+	}
+	defs, err := unmarshalDefinitions(&aux.Definitions)
+	if err != nil {
+		return err
+	}
 	*s = Schema{
-		Files: aux.Files,
-		Root:  aux.Root.Node,
+		Files:       aux.Files,
+		Definitions: defs,
+		Root:        root.Node,
+	}
+	resolveRefs(s.Root, defs)
+	for _, def := range defs {
+		resolveRefs(def, defs)
 	}
 	return nil
 }
 
+// unmarshalDefinitions unmarshals mapping of definitions.
+// Like fieldMap, it supports inline null definitions.
+func unmarshalDefinitions(value *yaml.Node) (map[string]Node, error) {
+	if value.Kind == 0 || value.ShortTag() == "!!null" {
+		return make(map[string]Node), nil
+	}
+	if value.Kind != yaml.MappingNode {
+		return nil, &yamlError{
+			line: value.Line,
+			err:  fmt.Errorf("definitions should be a mapping, got: %s", value.Tag),
+		}
+	}
+	defs := make(map[string]Node, len(value.Content)/2)
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		var name string
+		if err := value.Content[i].Decode(&name); err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(name, "../") {
+			return nil, &yamlError{
+				line: value.Content[i].Line,
+				err:  fmt.Errorf("definition name should not start with \"../\": %q", name),
+			}
+		}
+		v := value.Content[i+1]
+		if v.ShortTag() == "!!null" && v.Value == "" {
+			return nil, &yamlError{
+				line: v.Line,
+				err:  fmt.Errorf("empty definition: %q", name),
+			}
+		}
+		// UnmarshalYAML is called directly, because
+		// v.Decode skips nulls, but they are valid inline nodes
+		var def node
+		if err := def.UnmarshalYAML(v); err != nil {
+			return nil, err
+		}
+		defs[name] = def.Node
+	}
+	return defs, nil
+}
+
 func (s *Schema) GenerateJSON(ctx *Context, w io.Writer, r *rand.Rand) error {
 	if err := s.Root.GenerateJSON(ctx, w, r); err != nil {
 		return err
@@ -57,15 +117,47 @@ func (s *Schema) StreamJSON(ctx *Context, w io.Writer, r *rand.Rand, count int64
 }
 
 func (s *Schema) Validate() error {
-	return Walk(s.Root, func(n Node) (bool, error) {
-		if str, ok := n.(*String); ok {
-			if sf, ok := str.StringRander.(StringFile); ok {
-				fn := sf.Filename()
-				if _, found := s.Files[fn]; !found {
-					return false, fmt.Errorf("undefined file: %q", fn)
-				}
+	var errs Errors
+	errs.Add(Walk(s.Root, s.validateNode))
+	for _, p := range nodeRefs(s.Root) {
+		errs.Add(fmt.Errorf("%s: reference goes beyond root", p))
+	}
+	names := make([]string, 0, len(s.Definitions))
+	for name := range s.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		errs.Add(WrapErr("$"+name, Walk(s.Definitions[name], s.validateNode)))
+		// fields which refer to definition are not known,
+		// so its fields can refer only to each other
+		for _, p := range nodeRefs(s.Definitions[name]) {
+			errs.Add(WrapErr("$"+name, fmt.Errorf("%s: reference goes beyond definition", p)))
+		}
+	}
+	errs.Add(checkRefCycles(s.Definitions))
+	return errs.Err()
+}
+
+func (s *Schema) validateNode(n Node) (bool, error) {
+	switch n := n.(type) {
+	case *String:
+		switch sr := n.StringRander.(type) {
+		case StringFile:
+			if _, found := s.Files[sr.Filename()]; !found {
+				return false, fmt.Errorf("undefined file: %q", sr.Filename())
 			}
+		case *StringText:
+			if _, found := s.Files[sr.Markov]; sr.Markov != "" && !found {
+				return false, fmt.Errorf("undefined markov file: %q", sr.Markov)
+			}
+		}
+	case *Object:
+		return true, n.err
+	case *Ref:
+		if _, found := s.Definitions[n.Name]; !found {
+			return true, fmt.Errorf("undefined definition: %q", n.Name)
 		}
-		return true, nil
-	})
+	}
+	return true, nil
 }
@@ -0,0 +1,195 @@
+package schema
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSchema_Definitions(t *testing.T) {
+	var s Schema
+	require.NoError(t, yaml.Unmarshal([]byte(`
+definitions:
+  comment:
+    type: object
+    fields:
+      id: {type: int, choices: [1]}
+      replies:
+        ref: replies
+        maxDepth: 2
+        fallback:
+          type: const
+          value: []
+  replies:
+    type: array
+    length: 1
+    elements:
+      ref: comment
+root:
+  ref: comment
+`), &s))
+	require.NoError(t, s.Validate())
+
+	var w bytes.Buffer
+	ctx := NewContext()
+	ctx.SetSortKeys(true)
+	require.NoError(t, s.GenerateJSON(ctx, &w, rand.New(rand.NewSource(1))))
+	require.Equal(t, `{"id":1,"replies":[{"id":1,"replies":[{"id":1,"replies":[]}]}]}`+"\n", w.String())
+}
+
+func TestSchema_Definitions_InlineNull(t *testing.T) {
+	var s Schema
+	require.NoError(t, yaml.Unmarshal([]byte(`
+definitions:
+  nothing: null
+root:
+  type: array
+  length: 2
+  elements:
+    ref: nothing
+`), &s))
+	require.NoError(t, s.Validate())
+
+	var w bytes.Buffer
+	require.NoError(t, s.GenerateJSON(NewContext(), &w, rand.New(rand.NewSource(1))))
+	require.Equal(t, "[null,null]\n", w.String())
+
+	err := yaml.Unmarshal([]byte(`
+definitions:
+  empty:
+root: null
+`), &s)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `empty definition: "empty"`)
+
+	err = yaml.Unmarshal([]byte(`
+definitions:
+  ../x: null
+root: null
+`), &s)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `definition name should not start with "../"`)
+}
+
+func TestSchema_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name: "undefined reference",
+			data: `
+root:
+  type: object
+  fields:
+    a:
+      ref: undefined
+`,
+			wantErr: `.a: undefined definition: "undefined"`,
+		},
+		{
+			name: "undefined file in template",
+			data: `
+root:
+  type: object
+  fields:
+    email:
+      type: string
+      template: "{{user}}@{{domain}}"
+      parts:
+        user:
+          fake: username
+`,
+			wantErr: `.email{domain}: undefined file: "domain"`,
+		},
+		{
+			name: "undefined file in else",
+			data: `
+root:
+  type: object
+  fields:
+    kind: {type: string, choices: [a, b]}
+    name:
+      if: kind == "a"
+      then: {type: string, choices: [x]}
+      else: {type: string, from: names}
+`,
+			wantErr: `.name(else): undefined file: "names"`,
+		},
+		{
+			name: "undefined markov file",
+			data: `
+root:
+  type: object
+  fields:
+    review:
+      type: string
+      text:
+        markov: reviews
+`,
+			wantErr: `.review: undefined markov file: "reviews"`,
+		},
+		{
+			name: "field reference beyond definition",
+			data: `
+definitions:
+  end:
+    ref: ../start
+    offset: [1d, 7d]
+root:
+  type: object
+  fields:
+    start: {type: datetime}
+    end: {ref: end}
+`,
+			wantErr: "$end: ../start: reference goes beyond definition",
+		},
+		{
+			name: "expression beyond definition",
+			data: `
+definitions:
+  adult:
+    type: object
+    fields:
+      flag:
+        type: expr
+        expr: ../../age >= 18
+root:
+  type: object
+  fields:
+    age: {type: int}
+    adult: {ref: adult}
+`,
+			wantErr: "$adult: ../age: reference goes beyond definition",
+		},
+		{
+			name: "cycle without maxDepth",
+			data: `
+definitions:
+  a:
+    type: array
+    elements:
+      ref: b
+  b:
+    type: object
+    fields:
+      a:
+        ref: a
+root:
+  ref: a
+`,
+			wantErr: "definitions refer to each other without maxDepth: a -> b -> a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s Schema
+			require.NoError(t, yaml.Unmarshal([]byte(tt.data), &s))
+			require.EqualError(t, s.Validate(), tt.wantErr)
+		})
+	}
+}
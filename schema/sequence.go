@@ -0,0 +1,62 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+var defaultSequence = Sequence{
+	Start: 1,
+	Step:  1,
+}
+
+// Sequence generates successive numbers. Its state is kept in Context,
+// so it continues counting across root objects generated with it.
+type Sequence struct {
+	Start, Step int64
+	// Prefix and Padding make Sequence generate strings.
+	Prefix string
+	// Padding is a minimum number of digits, the number is padded with zeros.
+	Padding uint
+}
+
+func (s *Sequence) UnmarshalYAML(value *yaml.Node) error {
+	aux := struct {
+		Start   int64  `yaml:"start"`
+		Step    int64  `yaml:"step"`
+		Prefix  string `yaml:"prefix"`
+		Padding uint   `yaml:"padding"`
+	}{
+		Start: defaultSequence.Start,
+		Step:  defaultSequence.Step,
+	}
+	if err := value.Decode(&aux); err != nil {
+		return err
+	}
+	if aux.Step == 0 {
+		return &yamlError{
+			line: value.Line,
+			err:  errors.New("step should not be 0"),
+		}
+	}
+	*s = Sequence(aux)
+	return nil
+}
+
+func (s *Sequence) isString() bool {
+	return s.Prefix != "" || s.Padding > 0
+}
+
+func (s *Sequence) GenerateJSON(ctx *Context, w io.Writer, _ *rand.Rand) error {
+	num := ctx.nextInSequence(s)
+	if !s.isString() {
+		_, err := w.Write([]byte(strconv.FormatInt(num, 10)))
+		return err
+	}
+	return ctx.writeString(w, []byte(s.Prefix+fmt.Sprintf("%0*d", s.Padding, num)))
+}
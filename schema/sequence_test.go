@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSequence_GenerateJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		wantW string
+	}{
+		{
+			name:  "default",
+			data:  "{}",
+			wantW: "1,2,3",
+		},
+		{
+			name:  "start and step",
+			data:  "{start: 1000, step: -10}",
+			wantW: "1000,990,980",
+		},
+		{
+			name:  "prefix and padding",
+			data:  "{start: 122, prefix: ORD-, padding: 6}",
+			wantW: `"ORD-000122","ORD-000123","ORD-000124"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s Sequence
+			require.NoError(t, yaml.Unmarshal([]byte(tt.data), &s))
+			var w bytes.Buffer
+			ctx := NewContext()
+			for i := 0; i < 3; i++ {
+				if i > 0 {
+					w.WriteByte(',')
+				}
+				require.NoError(t, s.GenerateJSON(ctx, &w, nil))
+			}
+			require.Equal(t, tt.wantW, w.String())
+		})
+	}
+}
@@ -2,9 +2,9 @@ package schema
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"math/rand"
-	"strconv"
 
 	"gopkg.in/yaml.v3"
 )
@@ -19,6 +19,44 @@ func (c StringChoices) Rand(_ *Context, r *rand.Rand) ([]byte, error) {
 	return []byte(c[r.Intn(len(c))]), nil
 }
 
+func (c StringChoices) Count(ctx *Context) (uint64, bool) {
+	vs, _, _ := c.Enumerate(ctx)
+	return uint64(len(vs)), true
+}
+
+func (c StringChoices) Enumerate(*Context) ([][]byte, bool, error) {
+	return c.enumerate(nil), true, nil
+}
+
+// enumerate returns distinct choices which are reachable with ws
+func (c StringChoices) enumerate(ws *weights) [][]byte {
+	vs := make([][]byte, 0, len(c))
+	seen := make(map[string]struct{}, len(c))
+	for i, s := range c {
+		if ws.reachable(i) {
+			vs = appendDistinct(vs, seen, []byte(s))
+		}
+	}
+	return vs
+}
+
+type weightedStringChoices struct {
+	StringChoices
+	weights *weights
+}
+
+func (c weightedStringChoices) Rand(_ *Context, r *rand.Rand) ([]byte, error) {
+	return []byte(c.StringChoices[c.weights.Index(r, len(c.StringChoices))]), nil
+}
+
+func (c weightedStringChoices) Count(*Context) (uint64, bool) {
+	return uint64(len(c.enumerate(c.weights))), true
+}
+
+func (c weightedStringChoices) Enumerate(*Context) ([][]byte, bool, error) {
+	return c.enumerate(c.weights), true, nil
+}
+
 type StringFile string
 
 func (f StringFile) Filename() string {
@@ -29,31 +67,154 @@ func (f StringFile) Rand(ctx *Context, r *rand.Rand) ([]byte, error) {
 	return ctx.Rand(r, string(f))
 }
 
+func (f StringFile) Count(ctx *Context) (uint64, bool) {
+	l, err := ctx.fileLines(string(f))
+	if err != nil {
+		return 0, false
+	}
+	return l.count, true
+}
+
+func (f StringFile) Enumerate(ctx *Context) ([][]byte, bool, error) {
+	l, err := ctx.fileLines(string(f))
+	if err != nil || l.lines == nil {
+		return nil, false, err
+	}
+	return append([][]byte(nil), l.lines...), true, nil
+}
+
 type String struct {
 	StringRander
 }
 
 func (s *String) UnmarshalYAML(value *yaml.Node) error {
 	var tmp struct {
-		From    string   `yaml:"from"`
-		Choices []string `yaml:"choices"`
+		From      string             `yaml:"from"`
+		Choices   *weightedChoices   `yaml:"choices"`
+		Weights   []float64          `yaml:"weights"`
+		Pattern   string             `yaml:"pattern"`
+		MaxRepeat *int               `yaml:"maxRepeat"`
+		Charset   string             `yaml:"charset"`
+		Chars     string             `yaml:"chars"`
+		Length    *Length            `yaml:"length"`
+		ID        IDKind             `yaml:"id"`
+		Alphabet  string             `yaml:"alphabet"`
+		Fake      string             `yaml:"fake"`
+		Template  string             `yaml:"template"`
+		Parts     map[string]*String `yaml:"parts"`
+		Text      *StringText        `yaml:"text"`
 	}
 	if err := value.Decode(&tmp); err != nil {
 		return err
 	}
 
-	if !trueOnlyOne(tmp.From == "", len(tmp.Choices) == 0) {
+	if !trueOnlyOne(
+		tmp.From != "",
+		tmp.Choices != nil && len(tmp.Choices.values) != 0,
+		tmp.Pattern != "",
+		tmp.Charset != "",
+		tmp.Chars != "",
+		tmp.ID != "",
+		tmp.Fake != "",
+		tmp.Template != "",
+		tmp.Text != nil,
+	) {
 		return &yamlError{
 			line: value.Line,
-			err:  errors.New("string should have either from or choices"),
+			err:  errors.New("string should have one of: from, choices, pattern, charset, chars, id, fake, template, text"),
 		}
 	}
 
+	length := defaultCharsetLength
+	if tmp.Length != nil {
+		length = *tmp.Length
+	}
 	switch {
+	case tmp.Text != nil:
+		s.StringRander = tmp.Text
+		return nil
+	case tmp.Template != "":
+		t, err := NewStringTemplate(tmp.Template, tmp.Parts)
+		if err != nil {
+			return &yamlError{
+				line: value.Line,
+				err:  fmt.Errorf("template: %w", err),
+			}
+		}
+		s.StringRander = t
+		return nil
+	case tmp.Fake != "":
+		f, err := NewStringFake(tmp.Fake)
+		if err != nil {
+			return &yamlError{
+				line: value.Line,
+				err:  err,
+			}
+		}
+		s.StringRander = f
+		return nil
+	case tmp.ID != "":
+		id, err := NewStringID(tmp.ID, tmp.Alphabet, tmp.Length)
+		if err != nil {
+			return &yamlError{
+				line: value.Line,
+				err:  err,
+			}
+		}
+		s.StringRander = id
+		return nil
+	case tmp.Charset != "":
+		cs, found := NewStringCharset(tmp.Charset, length)
+		if !found {
+			return &yamlError{
+				line: value.Line,
+				err:  fmt.Errorf("unknown charset: %q", tmp.Charset),
+			}
+		}
+		s.StringRander = cs
+		return nil
+	case tmp.Chars != "":
+		s.StringRander = NewStringChars(tmp.Chars, length)
+		return nil
+	case tmp.Pattern != "":
+		maxRepeat := defaultMaxRepeat
+		if tmp.MaxRepeat != nil {
+			maxRepeat = *tmp.MaxRepeat
+		}
+		p, err := NewStringPattern(tmp.Pattern, maxRepeat)
+		if err != nil {
+			return &yamlError{
+				line: value.Line,
+				err:  fmt.Errorf("pattern: %w", err),
+			}
+		}
+		s.StringRander = p
+		return nil
 	case tmp.From != "":
 		s.StringRander = StringFile(tmp.From)
-	case len(tmp.Choices) != 0:
-		s.StringRander = StringChoices(tmp.Choices)
+	case tmp.Choices != nil:
+		choices := make(StringChoices, len(tmp.Choices.values))
+		ws, err := unmarshalChoices(value, tmp.Choices, tmp.Weights, func(i int, v *yaml.Node) error {
+			return v.Decode(&choices[i])
+		})
+		if err != nil {
+			return err
+		}
+		if ws == nil {
+			s.StringRander = choices
+		} else {
+			s.StringRander = weightedStringChoices{
+				StringChoices: choices,
+				weights:       ws,
+			}
+		}
+		return nil
+	}
+	if tmp.Weights != nil {
+		return &yamlError{
+			line: value.Line,
+			err:  errors.New("weights are given without choices"),
+		}
 	}
 	return nil
 }
@@ -64,8 +225,46 @@ func (s *String) GenerateJSON(ctx *Context, w io.Writer, r *rand.Rand) error {
 		return err
 	}
 
-	_, err = w.Write([]byte(strconv.Quote(string(str))))
-	return err
+	return ctx.writeString(w, str)
+}
+
+func (s *String) Walk(fn WalkFn) error {
+	if w, ok := s.StringRander.(Walker); ok {
+		return w.Walk(fn)
+	}
+	return nil
+}
+
+func (s *String) refPaths() []RefPath {
+	if r, ok := s.StringRander.(referrer); ok {
+		return r.refPaths()
+	}
+	return nil
+}
+
+func (s *String) Count(ctx *Context) (uint64, bool) {
+	if c, ok := s.StringRander.(Countable); ok {
+		return c.Count(ctx)
+	}
+	return 0, false
+}
+
+// Enumerate returns distinct strings encoded as JSON
+func (s *String) Enumerate(ctx *Context) ([][]byte, bool, error) {
+	e, ok := s.StringRander.(Enumerable)
+	if !ok {
+		return nil, false, nil
+	}
+	vs, ok, err := e.Enumerate(ctx)
+	if !ok || err != nil {
+		return nil, false, err
+	}
+	for i, v := range vs {
+		if vs[i], err = ctx.stringEncoder.Append(nil, v); err != nil {
+			return nil, false, err
+		}
+	}
+	return vs, true, nil
 }
 
 func trueOnlyOne(bs ...bool) bool {
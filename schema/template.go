@@ -0,0 +1,150 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+const (
+	templateOpen  = "{{"
+	templateClose = "}}"
+)
+
+// StringTemplate generates strings by substituting placeholders
+// like "{{name}}" with strings generated by parts.
+// Placeholders like "{{.name}}" are substituted with values of sibling fields.
+type StringTemplate struct {
+	segments []templateSegment
+	parts    map[string]*String
+	// names are names of parts in order of their first occurrence
+	names []string
+}
+
+// templateSegment is either a literal, a part or a reference
+type templateSegment struct {
+	literal []byte
+	part    *String
+	ref     *RefPath
+}
+
+// NewStringTemplate parses template. Placeholders refer to parts by their names.
+// Names that are not in parts refer to files.
+func NewStringTemplate(template string, parts map[string]*String) (*StringTemplate, error) {
+	t := &StringTemplate{
+		parts: make(map[string]*String, len(parts)),
+	}
+	used := make(map[string]bool, len(parts))
+	for s := template; s != ""; {
+		i := strings.Index(s, templateOpen)
+		if i < 0 {
+			t.segments = append(t.segments, templateSegment{literal: []byte(s)})
+			break
+		}
+		if i > 0 {
+			t.segments = append(t.segments, templateSegment{literal: []byte(s[:i])})
+		}
+		s = s[i+len(templateOpen):]
+		j := strings.Index(s, templateClose)
+		if j < 0 {
+			return nil, fmt.Errorf("unclosed %q", templateOpen)
+		}
+		name := strings.TrimSpace(s[:j])
+		s = s[j+len(templateClose):]
+		if name == "" {
+			return nil, errors.New("empty placeholder")
+		}
+		if strings.HasPrefix(name, ".") {
+			p, err := parseTemplateRef(name)
+			if err != nil {
+				return nil, err
+			}
+			t.segments = append(t.segments, templateSegment{ref: &p})
+			continue
+		}
+		part, found := t.parts[name]
+		if !found {
+			if part, found = parts[name]; !found {
+				part = &String{StringRander: StringFile(name)}
+			}
+			t.parts[name] = part
+			t.names = append(t.names, name)
+		}
+		used[name] = true
+		t.segments = append(t.segments, templateSegment{part: part})
+	}
+	for name := range parts {
+		if !used[name] {
+			return nil, fmt.Errorf("part %q is not used in template", name)
+		}
+	}
+	return t, nil
+}
+
+// parseTemplateRef parses references like ".name" to sibling fields
+// and nested ".address.city", or "../../name" like in FieldRef
+func parseTemplateRef(s string) (RefPath, error) {
+	if strings.HasPrefix(s, "../") {
+		return ParseRefPath(s)
+	}
+	p := RefPath{
+		Up:    1,
+		Names: strings.Split(s[1:], "."),
+	}
+	for _, name := range p.Names {
+		if name == "" {
+			return RefPath{}, fmt.Errorf("invalid reference: %q", s)
+		}
+	}
+	return p, nil
+}
+
+func (t *StringTemplate) Rand(ctx *Context, r *rand.Rand) ([]byte, error) {
+	var b bytes.Buffer
+	for _, s := range t.segments {
+		switch {
+		case s.part != nil:
+			str, err := s.part.Rand(ctx, r)
+			if err != nil {
+				return nil, err
+			}
+			b.Write(str)
+		case s.ref != nil:
+			v, err := ctx.fieldValue(*s.ref)
+			if err != nil {
+				return nil, err
+			}
+			var str string
+			if json.Unmarshal(v, &str) == nil {
+				b.WriteString(str)
+			} else {
+				// not a string, so it is written as JSON
+				b.Write(v)
+			}
+		default:
+			b.Write(s.literal)
+		}
+	}
+	return b.Bytes(), nil
+}
+
+func (t *StringTemplate) refPaths() []RefPath {
+	var refs []RefPath
+	for _, s := range t.segments {
+		if s.ref != nil {
+			refs = append(refs, *s.ref)
+		}
+	}
+	return refs
+}
+
+func (t *StringTemplate) Walk(fn WalkFn) error {
+	var errs Errors
+	for _, name := range t.names {
+		errs.Add(WrapErr("{"+name+"}", Walk(t.parts[name], fn)))
+	}
+	return errs.Err()
+}
@@ -0,0 +1,61 @@
+package schema
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestString_Template(t *testing.T) {
+	var s String
+	require.NoError(t, yaml.Unmarshal([]byte(`
+type: string
+template: "{{ user }}+{{tag}}@{{user}}.com"
+parts:
+  user:
+    choices: [alice]
+  tag:
+    type: string
+    pattern: "[0-9]{3}"
+`), &s))
+
+	var w bytes.Buffer
+	require.NoError(t, s.GenerateJSON(NewContext(), &w, rand.New(rand.NewSource(1))))
+	assert.Regexp(t, `^"alice\+[0-9]{3}@alice\.com"$`, w.String())
+}
+
+func TestNewStringTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		parts    map[string]*String
+		wantErr  string
+	}{
+		{
+			name:     "unclosed",
+			template: "{{a",
+			wantErr:  `unclosed "{{"`,
+		},
+		{
+			name:     "empty placeholder",
+			template: "a{{ }}",
+			wantErr:  "empty placeholder",
+		},
+		{
+			name:     "unused part",
+			template: "{{a}}",
+			parts:    map[string]*String{"b": {StringRander: StringChoices{"b"}}},
+			wantErr:  `part "b" is not used in template`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewStringTemplate(tt.template, tt.parts)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
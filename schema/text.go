@@ -0,0 +1,249 @@
+package schema
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	defaultTextWords = Length{
+		Min: 4,
+		Max: 12,
+	}
+	defaultTextSentences = Length{
+		Min: 1,
+		Max: 5,
+	}
+	defaultTextParagraphs = Length{
+		Min: 1,
+		Max: 1,
+	}
+)
+
+const (
+	defaultMarkovOrder = 2
+	// loremCommaProbability is a probability of comma after a word of lorem ipsum
+	loremCommaProbability = 0.1
+)
+
+// StringText generates prose of Paragraphs paragraphs,
+// each of Sentences sentences of Words words.
+// Words are taken from lorem ipsum or generated by Markov chain
+// trained on the file Markov.
+type StringText struct {
+	Words, Sentences, Paragraphs Length
+	// Markov is a name of file to train Markov chain on
+	Markov string
+	// Order is the number of previous words which the next word depends on
+	Order int
+}
+
+func (t *StringText) UnmarshalYAML(value *yaml.Node) error {
+	aux := struct {
+		Words      Length `yaml:"words"`
+		Sentences  Length `yaml:"sentences"`
+		Paragraphs Length `yaml:"paragraphs"`
+		Markov     string `yaml:"markov"`
+		Order      *int   `yaml:"order"`
+	}{
+		Words:      defaultTextWords,
+		Sentences:  defaultTextSentences,
+		Paragraphs: defaultTextParagraphs,
+	}
+	if err := value.Decode(&aux); err != nil {
+		return err
+	}
+	*t = StringText{
+		Words:      aux.Words,
+		Sentences:  aux.Sentences,
+		Paragraphs: aux.Paragraphs,
+		Markov:     aux.Markov,
+		Order:      defaultMarkovOrder,
+	}
+	if aux.Order != nil {
+		if aux.Markov == "" {
+			return &yamlError{
+				line: value.Line,
+				err:  errors.New("order is given without markov"),
+			}
+		}
+		t.Order = *aux.Order
+	}
+	if err := t.validate(); err != nil {
+		return &yamlError{
+			line: value.Line,
+			err:  err,
+		}
+	}
+	return nil
+}
+
+func (t *StringText) validate() error {
+	if t.Words.Min == 0 {
+		return errors.New("sentence should have at least 1 word")
+	}
+	if t.Sentences.Min == 0 {
+		return errors.New("paragraph should have at least 1 sentence")
+	}
+	if t.Order < 1 {
+		return errors.New("order should be positive")
+	}
+	return nil
+}
+
+func (t *StringText) Rand(ctx *Context, r *rand.Rand) ([]byte, error) {
+	var chain *markovChain
+	if t.Markov != "" {
+		var err error
+		if chain, err = ctx.markovChain(t.Markov, t.Order); err != nil {
+			return nil, err
+		}
+	}
+	var b []byte
+	for p, pn := uint64(0), t.Paragraphs.Rand(r); p < pn; p++ {
+		if p > 0 {
+			b = append(b, "\n\n"...)
+		}
+		for s, sn := uint64(0), t.Sentences.Rand(r); s < sn; s++ {
+			if s > 0 {
+				b = append(b, ' ')
+			}
+			start := len(b)
+			if chain != nil {
+				b = chain.appendWords(b, r, t.Words.Rand(r))
+			} else {
+				b = appendLorem(b, r, t.Words.Rand(r))
+			}
+			b = endSentence(b, start)
+		}
+	}
+	return b, nil
+}
+
+// appendLorem appends n words of lorem ipsum separated by spaces
+func appendLorem(b []byte, r *rand.Rand, n uint64) []byte {
+	for i := uint64(0); i < n; i++ {
+		if i > 0 {
+			if r.Float64() < loremCommaProbability && i < n-1 {
+				b = append(b, ',')
+			}
+			b = append(b, ' ')
+		}
+		b = append(b, loremWords[r.Intn(len(loremWords))]...)
+	}
+	return b
+}
+
+// endSentence capitalizes the sentence starting at b[start:]
+// and ends it with a period unless it already ends with punctuation
+func endSentence(b []byte, start int) []byte {
+	if c, size := utf8.DecodeRune(b[start:]); unicode.IsLower(c) {
+		up := appendRune(nil, unicode.ToUpper(c))
+		b = append(b[:start], append(up, b[start+size:]...)...)
+	}
+	b = b[:start+len(bytes.TrimRight(b[start:], ",;:"))]
+	if len(b) > start && !strings.ContainsAny(string(b[len(b)-1:]), ".!?") {
+		b = append(b, '.')
+	}
+	return b
+}
+
+// markovChain is a Markov chain of words, where the next word
+// depends on order previous ones
+type markovChain struct {
+	order int
+	// next are words which follow the state, the empty word ends
+	// the sentence. State is joined previous words, starting from
+	// empty ones at the beginning of sentence.
+	next map[string][]string
+}
+
+// newMarkovChain trains Markov chain of given order on lines of src.
+// Sentences end at the end of line and after words ending with ".", "!" or "?".
+func newMarkovChain(src LineSource, order int) (*markovChain, error) {
+	c := &markovChain{
+		order: order,
+		next:  make(map[string][]string),
+	}
+	state := make([]string, order)
+	for i := 0; i < src.Len(); i++ {
+		line, err := src.Line(i)
+		if err != nil {
+			return nil, err
+		}
+		words := strings.Fields(string(line))
+		for j, word := range words {
+			if j == 0 || strings.ContainsAny(words[j-1][len(words[j-1])-1:], ".!?") {
+				if j > 0 {
+					c.add(state, "")
+				}
+				state = make([]string, order)
+			}
+			c.add(state, word)
+			state = append(state[1:], word)
+		}
+		if len(words) > 0 {
+			c.add(state, "")
+		}
+	}
+	if len(c.next) == 0 {
+		return nil, errors.New("there are no words to train on")
+	}
+	return c, nil
+}
+
+func (c *markovChain) add(state []string, word string) {
+	key := strings.Join(state, " ")
+	c.next[key] = append(c.next[key], word)
+}
+
+// appendWords appends n words generated by the chain separated by spaces.
+// If sentence ends before n words, the next one is started.
+func (c *markovChain) appendWords(b []byte, r *rand.Rand, n uint64) []byte {
+	state := make([]string, c.order)
+	for i := uint64(0); i < n; {
+		next := c.next[strings.Join(state, " ")]
+		word := next[r.Intn(len(next))]
+		if word == "" {
+			state = make([]string, c.order)
+			continue
+		}
+		if i > 0 {
+			b = append(b, ' ')
+		}
+		b = append(b, word...)
+		state = append(state[1:], word)
+		i++
+	}
+	return b
+}
+
+// markovChainKey identifies Markov chains cached in Context
+type markovChainKey struct {
+	file  string
+	order int
+}
+
+var loremWords = strings.Fields(`
+lorem ipsum dolor sit amet consectetur adipiscing elit sed do eiusmod tempor
+incididunt ut labore et dolore magna aliqua enim ad minim veniam quis nostrud
+exercitation ullamco laboris nisi aliquip ex ea commodo consequat duis aute
+irure in reprehenderit voluptate velit esse cillum eu fugiat nulla pariatur
+excepteur sint occaecat cupidatat non proident sunt culpa qui officia deserunt
+mollit anim id est laborum curabitur pretium tincidunt lacus nunc pulvinar
+sapien ligula vestibulum ante primis faucibus orci luctus ultrices posuere
+cubilia curae mauris vitae ultricies leo integer malesuada nam libero justo
+laoreet sagittis quam pellentesque nec nisl morbi tristique senectus netus fames
+turpis egestas maecenas pharetra convallis purus viverra accumsan felis donec
+massa scelerisque eleifend vulputate mi aenean euismod elementum nibh praesent
+semper feugiat condimentum lacinia at augue neque gravida arcu fermentum iaculis
+eget diam volutpat blandit cursus risus tellus mattis molestie ac placerat
+rhoncus urna porttitor quisque sollicitudin dictum varius hendrerit dapibus odio
+facilisis suspendisse potenti nullam imperdiet
+`)
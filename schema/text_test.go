@@ -0,0 +1,156 @@
+package schema
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestStringText_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    StringText
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			data: `{}`,
+			want: StringText{
+				Words:      defaultTextWords,
+				Sentences:  defaultTextSentences,
+				Paragraphs: defaultTextParagraphs,
+				Order:      defaultMarkovOrder,
+			},
+		},
+		{
+			name: "markov",
+			data: `{words: [2, 3], sentences: 1, paragraphs: [1, 2], markov: reviews, order: 1}`,
+			want: StringText{
+				Words:      Length{Min: 2, Max: 3},
+				Sentences:  Length{Min: 1, Max: 1},
+				Paragraphs: Length{Min: 1, Max: 2},
+				Markov:     "reviews",
+				Order:      1,
+			},
+		},
+		{
+			name:    "no words",
+			data:    `{words: [0, 3]}`,
+			wantErr: "sentence should have at least 1 word",
+		},
+		{
+			name:    "no sentences",
+			data:    `{sentences: 0}`,
+			wantErr: "paragraph should have at least 1 sentence",
+		},
+		{
+			name:    "order without markov",
+			data:    `{order: 3}`,
+			wantErr: "order is given without markov",
+		},
+		{
+			name:    "zero order",
+			data:    `{markov: reviews, order: 0}`,
+			wantErr: "order should be positive",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got StringText
+			err := yaml.Unmarshal([]byte(tt.data), &got)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStringText_Rand_Lorem(t *testing.T) {
+	s := &StringText{
+		Words:      Length{Min: 3, Max: 3},
+		Sentences:  Length{Min: 2, Max: 2},
+		Paragraphs: Length{Min: 2, Max: 2},
+	}
+	sentence := `[A-Z][a-z]+(,? [a-z]+){2}\.`
+	re := regexp.MustCompile(`^` + sentence + ` ` + sentence + `\n\n` + sentence + ` ` + sentence + `$`)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		b, err := s.Rand(NewContext(), r)
+		require.NoError(t, err)
+		assert.Regexp(t, re, string(b))
+	}
+}
+
+func TestStringText_Rand_Markov(t *testing.T) {
+	f, err := ioutil.TempFile("", "reviews")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString("great product, works fine. would buy again!\nfast delivery\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	ctx := NewContext()
+	defer ctx.Close()
+	require.NoError(t, ctx.AddFile("reviews", f.Name()))
+
+	s := &StringText{
+		Words:      Length{Min: 1, Max: 10},
+		Sentences:  Length{Min: 1, Max: 1},
+		Paragraphs: Length{Min: 1, Max: 1},
+		Markov:     "reviews",
+		Order:      1,
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		b, err := s.Rand(ctx, r)
+		require.NoError(t, err)
+		words := strings.Fields(string(b))
+		require.NotEmpty(t, words)
+		require.LessOrEqual(t, len(words), 10)
+		// order 1 chain of the file can only start with these words
+		assert.Contains(t, []string{"Great", "Would", "Fast"}, strings.TrimRight(words[0], ".!?"))
+		assert.Regexp(t, `[.!?]$`, string(b))
+	}
+}
+
+func TestNewMarkovChain(t *testing.T) {
+	src := newBufferedLineSource(maxMemSize)
+	require.NoError(t, src.WriteLine([]byte("a b. a c")))
+	require.NoError(t, src.WriteLine([]byte("")))
+
+	c, err := newMarkovChain(src, 1)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"":   {"a", "a"},
+		"a":  {"b.", "c"},
+		"b.": {""},
+		"c":  {""},
+	}, c.next)
+
+	_, err = newMarkovChain(newBufferedLineSource(maxMemSize), 1)
+	assert.Error(t, err)
+}
+
+func TestEndSentence(t *testing.T) {
+	tests := map[string]string{
+		"hello world":  "Hello world.",
+		"hello world,": "Hello world.",
+		"is it?":       "Is it?",
+		"état":         "État.",
+	}
+	for in, want := range tests {
+		assert.Equal(t, "x "+want, string(endSentence([]byte("x "+in), 2)))
+	}
+}
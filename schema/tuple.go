@@ -0,0 +1,110 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Tuple is an array of items of different types at fixed positions
+type Tuple struct {
+	Items []Node
+	// AdditionalItems are generated after Items, nil means no additional items
+	AdditionalItems Node
+	// Length is the total number of elements. If it is less than
+	// the number of items, only first items are generated.
+	Length Length
+}
+
+func (t *Tuple) UnmarshalYAML(value *yaml.Node) error {
+	var aux struct {
+		Items []yaml.Node `yaml:"items"`
+		// yaml.Node is used to support inline null,
+		// its Kind is 0 if additionalItems are not given
+		AdditionalItems yaml.Node `yaml:"additionalItems"`
+		Length          *Length   `yaml:"length"`
+	}
+	if err := value.Decode(&aux); err != nil {
+		return err
+	}
+	if len(aux.Items) == 0 {
+		return &yamlError{
+			line: value.Line,
+			err:  errors.New("\"items\" should not be empty"),
+		}
+	}
+	*t = Tuple{
+		Items: make([]Node, 0, len(aux.Items)),
+		Length: Length{
+			Min: uint64(len(aux.Items)),
+			Max: uint64(len(aux.Items)),
+		},
+	}
+	for i := range aux.Items {
+		// node is unmarshaled directly to support inline null
+		var n node
+		if err := n.UnmarshalYAML(&aux.Items[i]); err != nil {
+			return err
+		}
+		t.Items = append(t.Items, n.Node)
+	}
+	if aux.AdditionalItems.Kind != 0 {
+		var n node
+		if err := n.UnmarshalYAML(&aux.AdditionalItems); err != nil {
+			return err
+		}
+		t.AdditionalItems = n.Node
+	}
+	if aux.Length != nil {
+		t.Length = *aux.Length
+	}
+	if t.AdditionalItems == nil && t.Length.Max > uint64(len(t.Items)) {
+		return &yamlError{
+			line: value.Line,
+			err:  fmt.Errorf("length is greater than the number of items (%d) without additionalItems", len(t.Items)),
+		}
+	}
+	return nil
+}
+
+func (t *Tuple) GenerateJSON(ctx *Context, w io.Writer, r *rand.Rand) error {
+	if _, err := w.Write([]byte{'['}); err != nil {
+		return err
+	}
+	elNum := t.Length.Rand(r)
+	for i := uint64(0); i < elNum; i++ {
+		if i > 0 {
+			if _, err := w.Write([]byte{','}); err != nil {
+				return err
+			}
+		}
+		n := t.AdditionalItems
+		if i < uint64(len(t.Items)) {
+			n = t.Items[i]
+		}
+		if err := n.GenerateJSON(ctx, w, r); err != nil {
+			return t.wrapIndexErr(i, err)
+		}
+	}
+	_, err := w.Write([]byte{']'})
+	return err
+}
+
+func (t *Tuple) Walk(fn WalkFn) error {
+	var errs Errors
+	for i, n := range t.Items {
+		errs.Add(t.wrapIndexErr(uint64(i), Walk(n, fn)))
+	}
+	if t.AdditionalItems != nil {
+		errs.Add(WrapErr("[]", Walk(t.AdditionalItems, fn)))
+	}
+	return errs.Err()
+}
+
+func (t *Tuple) wrapIndexErr(ind uint64, err error) error {
+	return WrapErr("["+strconv.FormatUint(ind, 10)+"]", err)
+}
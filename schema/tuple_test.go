@@ -0,0 +1,107 @@
+package schema
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestTuple_GenerateJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		wantW string
+	}{
+		{
+			name: "items",
+			data: `
+items:
+  - {type: int, choices: [1]}
+  - {type: string, choices: [a]}
+  - null
+`,
+			wantW: `[1,"a",null]`,
+		},
+		{
+			name: "prefix",
+			data: `
+items:
+  - {type: int, choices: [1]}
+  - {type: string, choices: [a]}
+length: 1
+`,
+			wantW: `[1]`,
+		},
+		{
+			name: "additional items",
+			data: `
+items:
+  - {type: int, choices: [1]}
+additionalItems: {type: const, value: x}
+length: 3
+`,
+			wantW: `[1,"x","x"]`,
+		},
+		{
+			name: "null additional items",
+			data: `
+items:
+  - {type: int, choices: [1]}
+additionalItems: null
+length: 2
+`,
+			wantW: `[1,null]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tp Tuple
+			require.NoError(t, yaml.Unmarshal([]byte(tt.data), &tp))
+			var w bytes.Buffer
+			require.NoError(t, tp.GenerateJSON(NewContext(), &w, rand.New(rand.NewSource(1))))
+			assert.Equal(t, tt.wantW, w.String())
+		})
+	}
+}
+
+func TestTuple_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name:    "no items",
+			data:    "{length: 1}",
+			wantErr: `line 1: "items" should not be empty`,
+		},
+		{
+			name:    "too long without additional items",
+			data:    "{items: [int], length: [1, 2]}",
+			wantErr: "line 1: length is greater than the number of items (1) without additionalItems",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tp Tuple
+			assert.EqualError(t, yaml.Unmarshal([]byte(tt.data), &tp), tt.wantErr)
+		})
+	}
+}
+
+func TestTuple_Walk(t *testing.T) {
+	tp := Tuple{
+		Items:           []Node{errNode{}, Null{}},
+		AdditionalItems: errNode{},
+	}
+	assert.EqualError(t, Walk(&tp, func(n Node) (bool, error) {
+		if _, ok := n.(errNode); ok {
+			return true, assert.AnError
+		}
+		return true, nil
+	}), "[0]: "+assert.AnError.Error()+"; []: "+assert.AnError.Error())
+}
@@ -0,0 +1,136 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// maxDuplicates is a number of duplicates generated in a row for
+// a unique value before giving up, if the number of distinct values
+// is unknown, and a minimum of it otherwise
+const maxDuplicates = 1000
+
+// maxEnumerated is a maximum number of distinct values
+// which are listed to sample unique values from them
+const maxEnumerated = 1 << 16
+
+// collectorSlack is a factor of expected number of duplicates in a row
+// which are allowed while sampling unique values from a known number of
+// distinct values. It is exceeded with probability less than e^-collectorSlack.
+const collectorSlack = 20
+
+// Enumerable is implemented by nodes which can list
+// all distinct values they can generate.
+// StringRanders implement it for strings before they are encoded as JSON.
+type Enumerable interface {
+	// Enumerate returns all distinct values. It returns false
+	// if there are more than maxEnumerated of them.
+	Enumerate(ctx *Context) ([][]byte, bool, error)
+}
+
+// sampleUnique returns n distinct values of domain, which is a Node or
+// a StringRander, generated by gen. Duplicates generated in a row are
+// limited by maxDuplicatesOf. If n is at least half of distinct values and
+// domain is Enumerable, values are taken from its shuffled values instead.
+// what is a name of values in errors and wrap adds index of value to errors.
+func sampleUnique(ctx *Context, r *rand.Rand, n uint64, domain interface{}, what string,
+	gen func() ([]byte, error), wrap func(i uint64, err error) error) ([][]byte, error) {
+	cnt, counted := uint64(0), false
+	if c, ok := domain.(Countable); ok {
+		cnt, counted = c.Count(ctx)
+	}
+	if counted && cnt < n {
+		return nil, fmt.Errorf("unable to generate %d unique %ss: there are only %d distinct values", n, what, cnt)
+	}
+	if e, ok := domain.(Enumerable); ok && counted && cnt <= maxEnumerated && 2*n >= cnt {
+		all, ok, err := e.Enumerate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if ok && uint64(len(all)) < n {
+			// Count of some nodes is an upper bound
+			return nil, fmt.Errorf("unable to generate %d unique %ss: there are only %d distinct values", n, what, len(all))
+		}
+		if ok {
+			// partial Fisher-Yates shuffle
+			for i := 0; uint64(i) < n; i++ {
+				j := i + r.Intn(len(all)-i)
+				all[i], all[j] = all[j], all[i]
+			}
+			return all[:n], nil
+		}
+	}
+	vals := make([][]byte, 0, n)
+	seen := make(map[string]struct{}, n)
+	for duplicates := uint64(0); uint64(len(vals)) < n; {
+		i := uint64(len(vals))
+		if limit := maxDuplicatesOf(cnt, counted, i); duplicates == limit {
+			return nil, wrap(i, fmt.Errorf("unable to generate unique %s: got %d duplicates in a row", what, limit))
+		}
+		v, err := gen()
+		if err != nil {
+			return nil, wrap(i, err)
+		}
+		if _, found := seen[string(v)]; found {
+			duplicates++
+			continue
+		}
+		duplicates = 0
+		seen[string(v)] = struct{}{}
+		vals = append(vals, v)
+	}
+	return vals, nil
+}
+
+// maxDuplicatesOf returns the number of duplicates in a row allowed
+// while sampling a new value when i of cnt distinct values are taken.
+// As in coupon collector's problem, a new value is drawn with
+// probability (cnt-i)/cnt, so the expected number of draws is cnt/(cnt-i).
+func maxDuplicatesOf(cnt uint64, counted bool, i uint64) uint64 {
+	if !counted {
+		return maxDuplicates
+	}
+	limit := math.Ceil(collectorSlack * float64(cnt) / float64(cnt-i))
+	if limit < maxDuplicates {
+		return maxDuplicates
+	}
+	return uint64(limit)
+}
+
+// appendDistinct appends values of vs which are not in seen to dst
+func appendDistinct(dst [][]byte, seen map[string]struct{}, vs ...[]byte) [][]byte {
+	for _, v := range vs {
+		if _, found := seen[string(v)]; !found {
+			seen[string(v)] = struct{}{}
+			dst = append(dst, v)
+		}
+	}
+	return dst
+}
+
+// fileLines are distinct lines of a file
+type fileLines struct {
+	count uint64
+	// lines are nil if there are more than maxEnumerated of them
+	lines [][]byte
+}
+
+func newFileLines(src LineSource) (*fileLines, error) {
+	var lines [][]byte
+	seen := make(map[string]struct{})
+	for i := 0; i < src.Len(); i++ {
+		line, err := src.Line(i)
+		if err != nil {
+			return nil, err
+		}
+		lines = appendDistinct(lines, seen, line)
+	}
+	f := &fileLines{
+		count: uint64(len(lines)),
+	}
+	if f.count <= maxEnumerated {
+		f.lines = lines
+	}
+	return f, nil
+}
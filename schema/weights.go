@@ -0,0 +1,188 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"gopkg.in/yaml.v3"
+)
+
+// weights is a table for sampling indexes with given weights
+// in O(1) using Vose's alias method
+type weights struct {
+	prob  []float64
+	alias []int
+	// positive tells whether weight of index is positive
+	positive []bool
+}
+
+func newWeights(ws []float64) (*weights, error) {
+	if len(ws) == 0 {
+		return nil, errors.New("no weights")
+	}
+	var sum float64
+	for _, w := range ws {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("weight should be non-negative finite number, got: %v", w)
+		}
+		sum += w
+	}
+	if sum == 0 {
+		return nil, errors.New("sum of weights should be positive")
+	}
+
+	n := len(ws)
+	t := &weights{
+		prob:     make([]float64, n),
+		alias:    make([]int, n),
+		positive: make([]bool, n),
+	}
+	scaled := make([]float64, n)
+	small, large := make([]int, 0, n), make([]int, 0, n)
+	for i, w := range ws {
+		t.positive[i] = w > 0
+		scaled[i] = w * float64(n) / sum
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+	for len(small) > 0 && len(large) > 0 {
+		s, l := small[len(small)-1], large[len(large)-1]
+		small, large = small[:len(small)-1], large[:len(large)-1]
+		t.prob[s], t.alias[s] = scaled[s], l
+		scaled[l] += scaled[s] - 1
+		if scaled[l] < 1 {
+			small = append(small, l)
+		} else {
+			large = append(large, l)
+		}
+	}
+	// the rest is equal to 1 up to floating point errors
+	for _, i := range append(small, large...) {
+		t.prob[i], t.alias[i] = 1, i
+	}
+	return t, nil
+}
+
+// Index returns random index in [0, n).
+// Nil weights means that all indexes are equally likely.
+func (t *weights) Index(r *rand.Rand, n int) int {
+	if t == nil {
+		return r.Intn(n)
+	}
+	i := r.Intn(len(t.prob))
+	if r.Float64() < t.prob[i] {
+		return i
+	}
+	return t.alias[i]
+}
+
+// reachable reports whether index i can be returned by Index
+func (t *weights) reachable(i int) bool {
+	return t == nil || t.positive[i]
+}
+
+// uniform reports whether all weights are equal
+func uniform(ws []float64) bool {
+	for _, w := range ws {
+		if w != ws[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// weightedChoices is a helper type for unmarshal choices
+// given either as plain values or as {value, weight} mappings
+type weightedChoices struct {
+	values []*yaml.Node
+	// weights are nil if none of choices has weight
+	weights []float64
+}
+
+func (c *weightedChoices) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.SequenceNode {
+		return &yamlError{
+			line: value.Line,
+			err:  fmt.Errorf("choices should be a sequence, got: %s", value.Tag),
+		}
+	}
+	*c = weightedChoices{
+		values: make([]*yaml.Node, 0, len(value.Content)),
+	}
+	ws := make([]float64, 0, len(value.Content))
+	var weighted bool
+	for _, v := range value.Content {
+		if v.Kind != yaml.MappingNode {
+			c.values = append(c.values, v)
+			ws = append(ws, 1)
+			continue
+		}
+		var aux struct {
+			Value  yaml.Node `yaml:"value"`
+			Weight *float64  `yaml:"weight"`
+		}
+		if err := v.Decode(&aux); err != nil {
+			return err
+		}
+		if aux.Value.Kind == 0 {
+			return &yamlError{
+				line: v.Line,
+				err:  errors.New("\"value\" is required"),
+			}
+		}
+		c.values = append(c.values, &aux.Value)
+		w := 1.0
+		if aux.Weight != nil {
+			w, weighted = *aux.Weight, true
+		}
+		ws = append(ws, w)
+	}
+	if weighted {
+		c.weights = ws
+	}
+	return nil
+}
+
+// table returns table of weights for choices. Weights can be given
+// either inline or separately with ws, but not in both ways.
+// It returns nil if all choices are equally likely.
+func (c *weightedChoices) table(ws []float64) (*weights, error) {
+	switch {
+	case c.weights != nil && ws != nil:
+		return nil, errors.New("weights should be given either inline or in \"weights\", not both")
+	case ws == nil:
+		ws = c.weights
+	case len(ws) != len(c.values):
+		return nil, fmt.Errorf("number of weights (%d) does not match number of choices (%d)", len(ws), len(c.values))
+	}
+	if ws == nil {
+		return nil, nil
+	}
+	t, err := newWeights(ws)
+	if err != nil || uniform(ws) {
+		return nil, err
+	}
+	return t, nil
+}
+
+// unmarshalChoices decodes each choice with decode and returns their weights
+func unmarshalChoices(value *yaml.Node, c *weightedChoices, ws []float64, decode func(i int, v *yaml.Node) error) (*weights, error) {
+	for i, v := range c.values {
+		if err := decode(i, v); err != nil {
+			return nil, err
+		}
+	}
+	t, err := c.table(ws)
+	if err != nil {
+		return nil, &yamlError{
+			line: value.Line,
+			err:  err,
+		}
+	}
+	return t, nil
+}
@@ -0,0 +1,82 @@
+package schema
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		ws      []float64
+		wantErr bool
+	}{
+		{
+			name: "equal",
+			ws:   []float64{1, 1, 1},
+		},
+		{
+			name: "with zero",
+			ws:   []float64{0, 1, 2},
+		},
+		{
+			name:    "empty",
+			ws:      nil,
+			wantErr: true,
+		},
+		{
+			name:    "negative",
+			ws:      []float64{1, -1},
+			wantErr: true,
+		},
+		{
+			name:    "all zeros",
+			ws:      []float64{0, 0},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newWeights(tt.ws)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestWeights_Index(t *testing.T) {
+	ws := []float64{0, 1, 3, 6}
+	tbl, err := newWeights(ws)
+	require.NoError(t, err)
+
+	const n = 100000
+	r := rand.New(rand.NewSource(1))
+	counts := make([]int, len(ws))
+	for i := 0; i < n; i++ {
+		counts[tbl.Index(r, len(ws))]++
+	}
+	assert.Zero(t, counts[0])
+	for i, w := range ws {
+		assert.InDelta(t, w/10, float64(counts[i])/n, 0.01, "index %d", i)
+	}
+}
+
+func TestWeights_reachable(t *testing.T) {
+	tbl := mustWeights(t, 0, 1, 3)
+	assert.False(t, tbl.reachable(0))
+	assert.True(t, tbl.reachable(1))
+	assert.True(t, tbl.reachable(2))
+	assert.True(t, (*weights)(nil).reachable(0))
+}
+
+func mustWeights(t *testing.T, ws ...float64) *weights {
+	tbl, err := newWeights(ws)
+	require.NoError(t, err)
+	return tbl
+}